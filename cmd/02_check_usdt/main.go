@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/token"
 	"log"
-	"math/big"
 	"os"
 )
 
@@ -52,10 +53,10 @@ func main() {
 	}
 	fmt.Printf("原生的余额： %s Wei \n", bal.String())
 
-	// 精度的转换（USDT 6 位小数） 100 USDT = 100,000,000
-	fBal := new(big.Float).SetInt(bal)
-	fDiv := new(big.Float).SetFloat64(1000000) // 10 ^ 6
-	realBal := new(big.Float).Quo(fBal, fDiv)
-
-	fmt.Printf("真实余额：%f USDT\n", realBal)
+	// 精度的转换，精度从合约读取（USDT 6 位小数） 100 USDT = 100,000,000
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("真实余额：%s %s\n", meta.Format(bal), meta.Symbol)
 }
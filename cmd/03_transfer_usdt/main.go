@@ -8,9 +8,9 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
-	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/token"
 	"log"
-	"math/big"
 	"os"
 )
 
@@ -60,25 +60,30 @@ func main() {
 		log.Fatal("err: 缺少 USDT 主网的合约地址")
 		return
 	}
-	usdt, err := erc20.NewERC20(usdtAddress, client)
+
+	// 准备转账的参数(账户2)，TO_WALLET_ADDR 可以是地址，也可以是地址簿中的名字
+	book := addressbook.LoadFromEnv()
+	toAddress, err := book.Resolve(chainId.Uint64(), os.Getenv("TO_WALLET_ADDR"))
 	if err != nil {
-		log.Fatal("err: 创建 USDT 合约实例失败")
+		log.Fatal("err: 缺少账户2地址 ", err)
 		return
 	}
 
-	// 准备转账的参数(账户2)
-	toAddress := common.HexToAddress(os.Getenv("TO_WALLET_ADDR"))
-	if toAddress.String() == "" {
-		log.Fatal("err: 缺少账户2地址")
+	// 转账 10 个代币，精度从合约读取 (USDT 精度是 6，所以是 10 * 10^6)
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddress)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败 ", err)
 		return
 	}
+	amount, err := meta.Parse("10")
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	fmt.Printf("正在准备转账,准备从 %s 转账 %s %s 给 %s ... \n", book.Label(chainId.Uint64(), auth.From), amount, meta.Symbol, book.Label(chainId.Uint64(), toAddress))
 
-	// 转账 10 USDT (精度是 6，所以是  10^6 )
-	amount := new(big.Int).Mul(big.NewInt(10), big.NewInt(1000000))
-	fmt.Printf("正在准备转账,准备从 %s 转账 10 USDT 给 %s ... \n", auth.From.Hex(), toAddress.Hex())
-
-	// 发起交易
-	tx, err := usdt.Transfer(auth, toAddress, amount)
+	// 发起交易，主网 USDT 的 transfer 没有返回值，使用 SafeTransfer 兼容
+	tx, err := token.SafeTransfer(auth, client, usdtAddress, toAddress, amount.Int())
 	if err != nil {
 		log.Fatal("err: 发起交易失败 ", err)
 		return
@@ -86,4 +91,16 @@ func main() {
 
 	// 打印交易的哈希
 	fmt.Printf("交易哈希(TX Hash): %s \n", tx.Hash().Hex())
+
+	// 广播成功不代表转账成功，等待上链后核对 Transfer 日志
+	check, err := token.VerifyTransfer(context.Background(), client, tx, usdtAddress, auth.From, toAddress, amount.Int())
+	if check == nil {
+		log.Fatal("err: 等待交易上链失败 ", err)
+		return
+	}
+	if err != nil {
+		log.Fatalf("err: 转账异常: %v (实际到账 %s %s)", err, meta.Format(check.Received), meta.Symbol)
+		return
+	}
+	fmt.Printf("转账成功，区块: %d \n", check.Receipt.BlockNumber)
 }
@@ -2,18 +2,31 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/backfill"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/nft"
+	"learn-web3-go/pkg/token"
 	"log"
-	"math/big"
 	"os"
 )
 
 func main() {
+	// 命令行参数
+	fromBlock := flag.Int64("from", -1, "起始区块, 默认为最近 1000 个区块")
+	toBlock := flag.Int64("to", -1, "结束区块, 默认为最新区块")
+	chunk := flag.Uint64("chunk", backfill.DefaultChunk, "初始每段查询的区块数, 会根据结果自动调整")
+	concurrency := flag.Int("concurrency", backfill.DefaultConcurrency, "同时查询的段数")
+	flag.Parse()
+
 	// 加载配置
 	godotenv.Load()
 
@@ -23,6 +36,10 @@ func main() {
 		log.Fatal("err: 连接节点失败", err)
 	}
 
+	// 加载地址簿，输出时展示标签
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(context.Background(), client).Uint64()
+
 	// 实例化合约
 	usdtAddress := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
 	if usdtAddress.String() == "" {
@@ -32,6 +49,10 @@ func main() {
 	if err != nil {
 		log.Fatal("err: 创建合约实例失败", err)
 	}
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddress)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败", err)
+	}
 
 	// 准备查询的范围，不仅需要查询最新的，还需要查询过去发生的
 	header, err := client.HeaderByNumber(context.Background(), nil)
@@ -41,58 +62,119 @@ func main() {
 
 	currentBlack := header.Number.Uint64()
 
-	// 设定查询范围：查询最近的 1000 个区块
+	// 设定查询范围：默认查询最近的 1000 个区块，可以用 -from / -to 查询任意范围
 	startBlock := uint64(0)
-	if currentBlack > 1000 {
+	if *fromBlock >= 0 {
+		startBlock = uint64(*fromBlock)
+	} else if currentBlack > 1000 {
 		startBlock = currentBlack - 1000
 	}
-
-	fmt.Printf("等待搜索区块范围: %d -> %d \n", startBlock, currentBlack)
-
-	// 创建过滤条件
-	filterOpts := &bind.FilterOpts{
-		Start:   startBlock,
-		End:     &currentBlack,
-		Context: context.Background(),
+	endBlock := currentBlack
+	if *toBlock >= 0 && uint64(*toBlock) < currentBlack {
+		endBlock = uint64(*toBlock)
 	}
 
-	// 设置过滤条件 FilterTransfer(opts, from[], to[])
-	// 查询所有“从 account0 发出的”或者“给 account0”的转账
+	fmt.Printf("等待搜索区块范围: %d -> %d \n", startBlock, endBlock)
+
+	// 设置过滤条件
+	// 查询所有“从 account0 发出的”转账
 	myWallet := common.HexToAddress(os.Getenv("MY_WALLET_ADDR"))
 	if myWallet.String() == "" {
 		log.Fatal("err: 缺少账户0地址")
 	}
 
-	// 参数 1：过滤参数，参数 2：从来拿来（account0）的地址，参数 3：去往 account2 的地址
-	iterator, err := usdt.FilterTransfer(filterOpts, []common.Address{myWallet}, nil)
+	// topic0 是 Transfer 事件签名，topic1 是 indexed 的 from
+	erc20ABI, err := erc20.ERC20MetaData.GetAbi()
 	if err != nil {
-		log.Fatal("err: 创建失败", err)
+		log.Fatal("err: 解析 ABI 失败", err)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{usdtAddress},
+		Topics:    [][]common.Hash{{erc20ABI.Events["Transfer"].ID}, {common.BytesToHash(myWallet.Bytes())}},
 	}
 
-	defer iterator.Close()
+	// 服务商限制了 eth_getLogs 的范围和结果数量，backfill 会分段并发查询，
+	// 结果太多时自动缩小范围，日志稀疏时放大范围，并按区块顺序交付
+	scanner := backfill.New(client, query).
+		WithChunk(*chunk, 0).
+		WithConcurrency(*concurrency).
+		WithProgress(func(p backfill.Progress) {
+			fmt.Fprintf(os.Stderr, "\r进度: %d/%d 区块, %d 条日志, 当前每段 %d 个区块", p.Scanned, p.Total, p.Logs, p.Chunk)
+		})
 
 	// 遍历结果
-	found := false
-	for iterator.Next() {
-		found = true
-		event := iterator.Event // 获取当前事件对象
+	found := 0
+	err = scanner.Run(context.Background(), startBlock, endBlock, func(vLog types.Log) error {
+		// 用合约绑定解析日志，格式不对的直接跳过
+		event, err := usdt.ParseTransfer(vLog)
+		if err != nil {
+			return nil
+		}
+		found++
 
 		// 输出发票日志
+		fmt.Println()
 		fmt.Println("------------------------------------------------------------")
 		fmt.Printf("发现转账小票\n")
 		fmt.Printf("交易哈希: %s\n", event.Raw.TxHash.Hex())
 		fmt.Printf("区块高度: %d\n", event.Raw.BlockNumber)
-		fmt.Printf("发送方 (From): %s\n", event.From.Hex())
-		fmt.Printf("接收方 (To):   %s\n", event.To.Hex())
+		fmt.Printf("发送方 (From): %s\n", book.Label(chainID, event.From))
+		fmt.Printf("接收方 (To):   %s\n", book.Label(chainID, event.To))
 
 		// 格式化金额
-		fVal := new(big.Float).SetInt(event.Value)
-		fVal.Quo(fVal, big.NewFloat(100000)) // 除以精度 6
-		fmt.Printf("金额：     %.6f USDT \n", fVal)
+		fmt.Printf("金额：     %s %s \n", meta.Format(event.Value), meta.Symbol)
+		return nil
+	})
+	fmt.Println()
+	if err != nil {
+		log.Fatal("err: 查询历史日志失败 ", err)
 	}
 
-	if !found {
+	if found == 0 {
 		fmt.Println("没有找到任何转账小票")
+	} else {
+		fmt.Printf("共 %d 笔转账\n", found)
+	}
+
+	// 设置了 NFT_CONTRACT_ADDR 时，再查询从 account0 转出的 ERC-1155 代币
+	if addr := os.Getenv("NFT_CONTRACT_ADDR"); common.IsHexAddress(addr) {
+		nftHistory(client, book, chainID, common.HexToAddress(addr), myWallet, startBlock, endBlock, *chunk)
 	}
+}
 
+// nftHistory 查询 contract 中从 wallet 转出的 ERC-1155 TransferSingle/TransferBatch 事件
+// ERC-1155 的 topic1 是 operator，from 在 topic2，批量转账按 id 拆开输出
+func nftHistory(client *ethclient.Client, book *addressbook.Book, chainID uint64, contract, wallet common.Address, start, end, chunk uint64) {
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{contract},
+		Topics:    [][]common.Hash{{nft.TransferSingleTopic, nft.TransferBatchTopic}, nil, {common.BytesToHash(wallet.Bytes())}},
+	}
+	found, skipped := 0, 0
+	err := backfill.New(client, query).WithChunk(chunk, 0).Run(context.Background(), start, end, func(vLog types.Log) error {
+		movements, err := nft.DecodeMovements(vLog)
+		if err != nil {
+			skipped++
+			log.Printf("warn: 无法解析日志 %s #%d: %v", vLog.TxHash.Hex(), vLog.Index, err)
+			return nil
+		}
+		for _, m := range movements {
+			found++
+			fmt.Println("------------------------------------------------------------")
+			fmt.Printf("发现 %s 转账\n", m.Kind)
+			fmt.Printf("交易哈希: %s\n", m.TxHash.Hex())
+			fmt.Printf("区块高度: %d\n", m.Block)
+			fmt.Printf("发送方 (From): %s\n", book.Label(chainID, m.From))
+			fmt.Printf("接收方 (To):   %s\n", book.Label(chainID, m.To))
+			fmt.Printf("TokenId: %s x %s\n", m.ID, m.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal("err: 查询 NFT 历史日志失败 ", err)
+	}
+	fmt.Printf("共 %d 笔 ERC-1155 转账", found)
+	if skipped > 0 {
+		fmt.Printf(", %d 条日志无法解析", skipped)
+	}
+	fmt.Println()
 }
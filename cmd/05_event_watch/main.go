@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/nft"
+	"learn-web3-go/pkg/token"
 	"log"
-	"math/big"
 	"os"
 )
 
@@ -31,6 +34,11 @@ func main() {
 		log.Fatal("err: 创建 USDT 合约实例失败")
 		return
 	}
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddress)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败", err)
+		return
+	}
 
 	// 创建一个 channel 通道接受事件
 	logs := make(chan *erc20.ERC20Transfer)
@@ -46,6 +54,24 @@ func main() {
 
 	fmt.Println("正在监听 Sepolia 链上的所有  USDT 转账记录")
 
+	// 设置了 NFT_CONTRACT_ADDR 时，同时监听该合约的 ERC-1155 TransferSingle/TransferBatch 事件
+	// 没有设置时 nftLogs 为 nil，select 不会读到它
+	var nftLogs chan types.Log
+	var nftErr <-chan error
+	if addr := os.Getenv("NFT_CONTRACT_ADDR"); common.IsHexAddress(addr) {
+		nftLogs = make(chan types.Log)
+		query := ethereum.FilterQuery{
+			Addresses: []common.Address{common.HexToAddress(addr)},
+			Topics:    [][]common.Hash{{nft.TransferSingleTopic, nft.TransferBatchTopic}},
+		}
+		nftSub, err := client.SubscribeFilterLogs(context.Background(), query, nftLogs)
+		if err != nil {
+			log.Fatal("err: 订阅 NFT 事件失败", err)
+		}
+		nftErr = nftSub.Err()
+		fmt.Printf("正在监听 %s 的 ERC-1155 转账\n", addr)
+	}
+
 	// 循环监听,接收事件
 	for {
 		select {
@@ -59,10 +85,25 @@ func main() {
 			fmt.Printf("To:   %s \n", vLog.To.Hex())
 
 			// 处理交易金额
-			val := new(big.Float).SetInt(vLog.Value)
-			val.Quo(val, big.NewFloat(1000000))
-			fmt.Printf("金额:  %.6f USDT", val)
+			fmt.Printf("金额:  %s %s", meta.Format(vLog.Value), meta.Symbol)
+
+		case err := <-nftErr:
+			log.Fatal("err: 监听 NFT 订阅失败", err)
 
+		case vLog := <-nftLogs:
+			// 批量转账按 id 拆成多条
+			movements, err := nft.DecodeMovements(vLog)
+			if err != nil {
+				log.Printf("warn: 无法解析日志 %s #%d: %v", vLog.TxHash.Hex(), vLog.Index, err)
+				continue
+			}
+			fmt.Printf("\n 捕捉到一笔 %s 转账\n", nft.TransferKindOf(vLog))
+			fmt.Printf("Tx:   %s \n", vLog.TxHash.Hex())
+			for _, m := range movements {
+				fmt.Printf("From: %s \n", m.From.Hex())
+				fmt.Printf("To:   %s \n", m.To.Hex())
+				fmt.Printf("TokenId: %s x %s\n", m.ID, m.Value)
+			}
 		}
 	}
 
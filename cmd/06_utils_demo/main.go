@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"learn-web3-go/utils"
+	"log"
 	"math/big"
 )
 
@@ -11,7 +12,10 @@ func main() {
 
 	// ----- ETH -> Wei -----
 	inputEth := "0.05"
-	wei := utils.EtherToWei(inputEth)
+	wei, err := utils.EtherToWei(inputEth)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Printf("输入: %s ETH\n", inputEth)
 	fmt.Printf("输出: %s Wei\n", wei.String())
 	fmt.Println("--------------------")
@@ -24,6 +28,13 @@ func main() {
 	fmt.Printf("输出: %s ETH\n", eth) // 应该去除多余的0
 	fmt.Println("--------------------")
 
+	// ----- 超过精度 -----
+	// 0.0000000000000000001 ETH 小于 1 Wei，直接报错而不是悄悄截断为 0
+	if _, err := utils.EtherToWei("0.0000000000000000001"); err != nil {
+		fmt.Println("超过精度:", err)
+	}
+	fmt.Println("--------------------")
+
 	// ----- 地址校验 -----
 	fmt.Println("--------- use utils -----------")
 	addr1 := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" // 正确
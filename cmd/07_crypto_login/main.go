@@ -1,12 +1,12 @@
 package main
 
 import (
-	"encoding/hex"
 	"fmt"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/signature"
 	"log"
-	"os"
 	"time"
 )
 
@@ -16,54 +16,42 @@ func main() {
 		log.Fatal("err: 找不到 .env 文件")
 		return
 	}
-	// 获取私钥
-	privateKey, err := crypto.HexToECDSA(os.Getenv("PRIVATE_KEY"))
-	if err != nil {
-		log.Fatal("err: 私钥转换错误")
-		return
-	}
-
-	// 推导公钥地址
-	userAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	fmt.Println("user address: ", userAddress.Hex())
+	// 获取私钥，推导公钥地址
+	user := model.NewUserFromEnv(nil)
+	fmt.Println("user address: ", user.Address.Hex())
 
 	// 构造消息
 	message := "welcome to study web3 with golang"
 
-	prefix := fmt.Sprintf("\x19Ethereum Signed Message: \n %d:%s", len(message), message)
-
-	// 计算哈希 (Keccak256)
-	hash := crypto.Keccak256Hash([]byte(prefix))
-	fmt.Printf("EIP-191 格式的 hash：%s", hash.Hex())
+	// 计算 EIP-191 personal_sign 的哈希 (Keccak256)
+	// "\x19Ethereum Signed Message:\n" + 消息长度 + 消息，和 MetaMask 签名的内容完全一致
+	hash := signature.PersonalMessageHash([]byte(message))
+	fmt.Printf("EIP-191 格式的 hash：%s\n", hash.Hex())
 
-	// 生成签名
-	// 使用私钥对这个哈希进行签名，得到 signature 是[]byte 字节数组
-	signature, err := crypto.Sign(hash.Bytes(), privateKey)
-	signature[64] += 27 // crypto 库生成的是 0 或 1, 需要手动+27
+	// 生成签名, v 已经是 27/28
+	sig, err := signature.SignPersonalMessage(user, []byte(message))
+	if err != nil {
+		log.Fatal("err: 签名失败", err)
+		return
+	}
 
 	// 转成 hex 字节显示
-	fmt.Printf("生成签名：0x%s\n", hex.EncodeToString(signature))
+	fmt.Printf("生成签名：%s\n", hexutil.Encode(sig))
 
 	// server 收到签名进行验证
 	fmt.Println("服务端正在验证签名中，请稍候...")
 	time.Sleep(3 * time.Second) // 模拟服务器验签的时间
-	if signature[64] >= 27 {
-		signature[64] -= 27
-	}
 
-	// 从签名中回复公钥（Recover）
-	sigPublicKey, err := crypto.SigToPub(hash.Bytes(), signature)
+	// 从签名中恢复地址（Recover）
+	recoveredAddress, err := signature.RecoverPersonalMessage([]byte(message), sig)
 	if err != nil {
 		log.Fatal("err: 签名恢复公钥失败")
 		return
 	}
-
-	// 算出地址
-	recoveredAddress := crypto.PubkeyToAddress(*sigPublicKey)
 	fmt.Println("后端回复出来的地址：", recoveredAddress.Hex())
 
 	// 验证地址是否一致
-	if recoveredAddress == userAddress {
+	if recoveredAddress == user.Address {
 		fmt.Println("签名验证通过")
 	} else {
 		fmt.Println("签名验证失败")
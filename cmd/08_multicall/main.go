@@ -1,19 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
-	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 
-	"learn-web3-go/contracts/erc20"
-	"learn-web3-go/contracts/multicall"
+	"learn-web3-go/pkg/multicall"
+	"learn-web3-go/pkg/token"
+	"learn-web3-go/utils"
 )
 
 func main() {
@@ -30,8 +31,6 @@ func main() {
 	}
 
 	// 3. 准备地址
-	// Multicall3
-	mcAddr := common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
 	// USDT 合约
 	usdtAddr := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
 	// 账户 1
@@ -39,79 +38,57 @@ func main() {
 	// 账户 2
 	otherAddr := common.HexToAddress(os.Getenv("TO_WALLET_ADDR"))
 
-	// 准备翻译器 (Parse ABI)
-	// 使用 ERC20 的 ABI 来打包/解包数据
-	erc20ABI, err := abi.JSON(strings.NewReader(erc20.ERC20MetaData.ABI))
-	if err != nil {
-		log.Fatal("解析 ERC20 ABI 失败:", err)
-	}
-
-	// 写小纸条 (Pack CallData)
-
-	// Task 1: 查我的余额
-	callData1, err := erc20ABI.Pack("balanceOf", myAddr)
-	if err != nil {
-		log.Fatal(err)
+	// 把调用放进购物车，每个调用返回一个 Future，Execute 之后才有结果
+	// 不需要手动 Pack/Unpack，也不需要按下标取结果再做类型断言
+	ctx := context.Background()
+	batch := multicall.New(client)
+
+	bal1 := batch.BalanceOf(usdtAddr, myAddr)
+	supply := batch.TotalSupply(usdtAddr)
+	bal2 := batch.BalanceOf(usdtAddr, otherAddr)
+	// 故意查询一个不是代币的地址: 旧的 aggregate 会让整批调用失败，aggregate3 只让这一个失败
+	bogus := batch.Decimals(myAddr)
+	// Multicall3 自带的查询，在同一个区块内读取 ETH 余额和区块信息
+	eth1 := batch.EthBalance(myAddr)
+	eth2 := batch.EthBalance(otherAddr)
+	blockNumber := batch.BlockNumber()
+	timestamp := batch.Timestamp()
+
+	// 发送请求，调用很多时会按 calldata 大小和 gas 自动拆成几批并发执行
+	if err := batch.Execute(ctx); err != nil {
+		log.Fatal("multicall 调用失败:", err)
 	}
 
-	// Task 2: 查总供应量
-	callData2, err := erc20ABI.Pack("totalSupply")
+	// 精度和符号同样通过一次 multicall 查询，结果会被缓存
+	meta, err := token.NewResolver(client).Get(ctx, usdtAddr)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Task 3: 查朋友的余额
-	callData3, err := erc20ABI.Pack("balanceOf", otherAddr)
-	if err != nil {
-		log.Fatal(err)
+	// 拆快递: 逐个检查结果，失败的调用只打印自己的错误
+	printAmount := func(label string, f *multicall.Future[*big.Int], format func(*big.Int) string) {
+		v, err := f.Get()
+		if err != nil {
+			fmt.Printf("%-12s 失败: %v\n", label, err)
+			return
+		}
+		fmt.Printf("%-12s %s\n", label, format(v))
 	}
-
-	// 将纸条放入购物车结构体
-	// 构建批量执行 3 个任务
-	calls := []multicall.Struct0{
-		{Target: usdtAddr, CallData: callData1},
-		{Target: usdtAddr, CallData: callData2},
-		{Target: usdtAddr, CallData: callData3},
-	}
-
-	mcInstance, err := multicall.NewMulticallCaller(mcAddr, client)
-	if err != nil {
-		log.Fatal("实例化 Multicall 失败:", err)
-	}
-
-	// 发送请求
-	aggregateResult, err := mcInstance.Aggregate(nil, calls)
-	if err != nil {
-		return
+	tokenAmount := func(v *big.Int) string { return meta.Format(v) + " " + meta.Symbol }
+	ether := func(v *big.Int) string { return utils.WeiToEther(v) + " ETH" }
+
+	printAmount("account1 的余额", bal1, tokenAmount)
+	printAmount("总供应量", supply, tokenAmount)
+	printAmount("account2 的余额", bal2, tokenAmount)
+	if decimals, err := bogus.Get(); err != nil {
+		fmt.Printf("%-12s 失败: %v\n", "account1 的 decimals", err)
+	} else {
+		fmt.Printf("%-12s %d\n", "account1 的 decimals", decimals)
 	}
-	results := aggregateResult.ReturnData
-
-	// 拆快递 (Unpack Result)
-
-	// --- 1. 解包我的余额 ---
-	out1, err := erc20ABI.Unpack("balanceOf", results[0])
-	if err != nil {
-		log.Fatal(err)
-	}
-	bal1 := out1[0].(*big.Int)
-	fmt.Printf("account1 的余额:   %s Wei\n", bal1.String())
-
-	// --- 2. 解包总供应量 ---
-	out2, err := erc20ABI.Unpack("totalSupply", results[1])
-	if err != nil {
-		log.Fatal(err)
-	}
-	supply := out2[0].(*big.Int)
-	// 简单除以 10^6 显示一点
-	humanSupply := new(big.Float).Quo(new(big.Float).SetInt(supply), big.NewFloat(1e6))
-	fmt.Printf("总供应量:   %.2f USDT\n", humanSupply)
-
-	// --- 3. 解包朋友余额 ---
-	out3, err := erc20ABI.Unpack("balanceOf", results[2])
-	if err != nil {
-		log.Fatal(err)
-	}
-	bal2 := out3[0].(*big.Int)
-	fmt.Printf("account2 的余额:   %s Wei\n", bal2.String())
-
+	printAmount("account1 的 ETH", eth1, ether)
+	printAmount("account2 的 ETH", eth2, ether)
+	printAmount("区块高度", blockNumber, (*big.Int).String)
+	printAmount("区块时间", timestamp, func(v *big.Int) string {
+		return time.Unix(v.Int64(), 0).Format(time.DateTime)
+	})
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/token"
 	"log"
 	"math/big"
 	"os"
@@ -80,11 +81,20 @@ func main() {
 	// 数据打包（Pack Data）
 	parsedABI, _ := abi.JSON(strings.NewReader(erc20.ERC20MetaData.ABI))
 
-	// 转账金额: 1 USDT 10^6
-	amount := big.NewInt(1000000)
+	// 转账金额: 1 个代币，精度从合约读取 (USDT 是 10^6)
+	meta, err := token.NewResolver(client).Get(ctx, usdtAddress)
+	if err != nil {
+		log.Fatal("查询代币信息失败 ", err)
+		return
+	}
+	amount, err := meta.Parse("1")
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
 
 	// pack 生成二进制
-	data, err := parsedABI.Pack("transfer", toAddress, amount)
+	data, err := parsedABI.Pack("transfer", toAddress, amount.Int())
 	if err != nil {
 		log.Fatal("pack 失败 ", err)
 		return
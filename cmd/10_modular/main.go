@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"github.com/ethereum/go-ethereum/common"
-	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
 	"learn-web3-go/pkg/chain"
 	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/token"
 	"learn-web3-go/utils"
 	"log"
-	"math/big"
 	"os"
 	"time"
 )
@@ -30,31 +31,57 @@ func main() {
 
 	// 连接合约
 	usdtAddress := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
-	toAddress := common.HexToAddress(os.Getenv("TO_WALLET_ADDR"))
+
+	// 接收方可以是地址，也可以是地址簿中的名字
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(context.Background(), client).Uint64()
+	toAddress, err := book.Resolve(chainID, os.Getenv("TO_WALLET_ADDR"))
+	if err != nil {
+		log.Fatal("toAddress 无效的地址", err)
+		return
+	}
 
 	// 是否是有效的地址
 	if !utils.IsValidAddress(toAddress.String()) {
 		log.Fatal("toAddress 无效的地址")
 		return
 	}
+	log.Printf("接收方: %s", book.Label(chainID, toAddress))
 
-	amount := big.NewInt(1000000) // 1 USDT
-
-	// 实例化合约
-	usdt, err := erc20.NewERC20(usdtAddress, client)
+	// 1 个代币，精度从合约读取
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddress)
+	if err != nil {
+		log.Fatal("查询代币信息失败", err)
+		return
+	}
+	amount, err := meta.Parse("1")
 	if err != nil {
-		log.Fatal("实例化合约失败", err)
+		log.Fatal(err)
 		return
 	}
+	log.Printf("转账金额: %s %s", amount, meta.Symbol)
 
 	// 发起交易
 	log.Println("正在广播交易中....")
 	time.Sleep(5 * time.Second)
 
-	tx, err := usdt.Transfer(auth, toAddress, amount)
+	// 发送前模拟执行，兼容没有返回值的代币
+	tx, err := token.SafeTransfer(auth, client, usdtAddress, toAddress, amount.Int())
 	if err != nil {
 		log.Fatal("发起交易失败", err)
 		return
 	}
-	log.Println("交易成功，交易 hash:", tx.Hash().Hex())
+	log.Println("交易已广播，交易 hash:", tx.Hash().Hex())
+
+	// 上链后核对 Transfer 日志，发现手续费或没有事件时报错
+	check, err := token.VerifyTransfer(context.Background(), client, tx, usdtAddress, auth.From, toAddress, amount.Int())
+	if check == nil {
+		log.Fatal("等待交易上链失败", err)
+		return
+	}
+	if err != nil {
+		log.Fatalf("转账异常: %v, 实际到账 %s %s, 差额 %s", err, meta.Format(check.Received), meta.Symbol, meta.Format(check.Fee))
+		return
+	}
+	log.Printf("交易成功，区块: %d", check.Receipt.BlockNumber)
 }
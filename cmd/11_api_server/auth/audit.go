@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultAuditPath 默认的拒绝访问日志
+const DefaultAuditPath = "./access-denied.log"
+
+// DeniedAttempt 一次被拒绝的访问
+type DeniedAttempt struct {
+	Time     time.Time `json:"time"`
+	Identity string    `json:"identity,omitempty"` // 地址或 API key 名字，未登录时为空
+	Role     Role      `json:"role,omitempty"`
+	Required Role      `json:"required,omitempty"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	ClientIP string    `json:"clientIp"`
+	Reason   string    `json:"reason"`
+}
+
+// AuditLog 以 JSON Lines 格式追加记录被拒绝的访问
+type AuditLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewAuditLog 创建审计日志
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Denied 记录一次被拒绝的访问，写文件失败时只打印日志
+func (a *AuditLog) Denied(attempt DeniedAttempt) {
+	if attempt.Time.IsZero() {
+		attempt.Time = time.Now()
+	}
+	log.Printf("拒绝访问: %s %s identity=%q role=%q required=%q %s",
+		attempt.Method, attempt.Path, attempt.Identity, attempt.Role, attempt.Required, attempt.Reason)
+
+	line, err := json.Marshal(attempt)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Println("warn: 写入审计日志失败,", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		log.Println("warn: 写入审计日志失败,", err)
+	}
+}
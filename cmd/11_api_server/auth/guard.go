@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-web3-go/cmd/11_api_server/response"
+	"learn-web3-go/pkg/apikey"
+)
+
+// maxSignedBodySize API key 签名请求的请求体上限
+const maxSignedBodySize = 1 << 20
+
+// ContextIdentityKey 当前身份在 gin.Context 中的 key
+const ContextIdentityKey = "identity"
+
+// 身份类型
+const (
+	IdentityAddress = "address" // SIWE 登录的钱包地址
+	IdentityAPIKey  = "apikey"  // HMAC 签名的 API key 请求
+)
+
+// 接口范围，API key 只能调用 scopes 中包含的接口，钱包登录不受限制
+const (
+	ScopeRead     = "read"     // 查询余额、配置
+	ScopeTransfer = "transfer" // 热钱包转账
+	ScopePermit   = "permit"   // 兑现 permit
+	ScopeAdmin    = "admin"    // 角色和 API key 管理
+	ScopeAll      = "*"        // 所有接口
+)
+
+// ParseScopes 检查签发 API key 时的 scopes，拼错的 scope 不会报错，只会让 key 调用接口时被拒绝
+// 去掉空白和重复项，不能为空
+func ParseScopes(scopes []string) ([]string, error) {
+	var out []string
+	for _, s := range scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		switch s {
+		case "":
+			continue
+		case ScopeRead, ScopeTransfer, ScopePermit, ScopeAdmin, ScopeAll:
+		default:
+			return nil, fmt.Errorf("未知的接口范围: %s", s)
+		}
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("接口范围不能为空")
+	}
+	return out, nil
+}
+
+// Identity 通过认证的调用方
+type Identity struct {
+	Kind   string   `json:"kind"`
+	ID     string   `json:"id"` // 钱包地址或 API key ID
+	Name   string   `json:"name,omitempty"`
+	Role   Role     `json:"role"`
+	Scopes []string `json:"scopes,omitempty"` // 只有 API key 有
+}
+
+// HasScope 判断身份是否可以调用 scope 范围的接口
+func (i *Identity) HasScope(scope string) bool {
+	if i.Kind != IdentityAPIKey || scope == "" {
+		return true
+	}
+	for _, s := range i.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Guard 认证 + 按角色授权，拒绝的请求写入审计日志
+type Guard struct {
+	SIWE    *SIWE
+	Roles   *Roles
+	APIKeys *apikey.Verifier
+	Audit   *AuditLog
+}
+
+// Authenticate 中间件: 识别调用方身份，支持 Authorization: Bearer <SIWE 会话 token> 和 HMAC 签名的 API key
+func (g *Guard) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(apikey.HeaderKeyID) != "" {
+			g.authenticateAPIKey(c)
+			return
+		}
+
+		session, ok := g.SIWE.Store.Session(bearerToken(c))
+		if !ok {
+			g.deny(c, http.StatusUnauthorized, nil, "", "未登录或登录已过期")
+			return
+		}
+		identity := &Identity{Kind: IdentityAddress, ID: session.Address.Hex()}
+		role, ok := g.Roles.AddressRole(session.Address)
+		if !ok {
+			g.deny(c, http.StatusForbidden, identity, "", "地址没有分配角色")
+			return
+		}
+		identity.Role = role
+		c.Set(ContextSessionKey, session)
+		c.Set(ContextIdentityKey, identity)
+		c.Next()
+	}
+}
+
+// authenticateAPIKey 验证 HMAC 签名，签名覆盖方法、路径、请求体哈希、时间戳和 nonce
+func (g *Guard) authenticateAPIKey(c *gin.Context) {
+	if g.APIKeys == nil {
+		g.deny(c, http.StatusUnauthorized, nil, "", "未启用 API key")
+		return
+	}
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBodySize+1))
+		if err != nil || len(body) > maxSignedBodySize {
+			g.deny(c, http.StatusBadRequest, nil, "", "请求体读取失败或过大")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	key, err := g.APIKeys.Verify(c.Request.Method, c.Request.URL.RequestURI(), c.Request.Header, body)
+	if err != nil {
+		g.deny(c, http.StatusUnauthorized, &Identity{Kind: IdentityAPIKey, ID: c.GetHeader(apikey.HeaderKeyID)}, "", err.Error())
+		return
+	}
+	identity := &Identity{Kind: IdentityAPIKey, ID: key.ID, Name: key.Name, Scopes: key.Scopes}
+	role, err := ParseRole(key.Role)
+	if err != nil {
+		g.deny(c, http.StatusForbidden, identity, "", "API key 的角色无效")
+		return
+	}
+	identity.Role = role
+	c.Set(ContextIdentityKey, identity)
+	c.Next()
+}
+
+// Require 中间件: 需要至少 role 的角色，API key 还需要拥有 scope，必须放在 Authenticate 之后
+func (g *Guard) Require(role Role, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := CurrentIdentity(c)
+		if !ok {
+			g.deny(c, http.StatusUnauthorized, nil, role, "未登录")
+			return
+		}
+		if !identity.Role.Allows(role) {
+			g.deny(c, http.StatusForbidden, identity, role, "权限不足")
+			return
+		}
+		if !identity.HasScope(scope) {
+			g.deny(c, http.StatusForbidden, identity, role, "API key 没有 "+scope+" 权限")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Deny 在处理函数中拒绝请求并记录，用于和请求内容相关的授权 (例如转账金额)
+func (g *Guard) Deny(c *gin.Context, required Role, reason string) {
+	identity, _ := CurrentIdentity(c)
+	g.deny(c, http.StatusForbidden, identity, required, reason)
+}
+
+func (g *Guard) deny(c *gin.Context, status int, identity *Identity, required Role, reason string) {
+	attempt := DeniedAttempt{
+		Required: required,
+		Method:   c.Request.Method,
+		Path:     c.FullPath(),
+		ClientIP: c.ClientIP(),
+		Reason:   reason,
+	}
+	if identity != nil {
+		attempt.Identity = identity.ID
+		attempt.Role = identity.Role
+	}
+	if attempt.Path == "" {
+		attempt.Path = c.Request.URL.Path
+	}
+	g.Audit.Denied(attempt)
+	response.Fail(c, status, reason)
+	c.Abort()
+}
+
+// CurrentIdentity 取出 Authenticate 写入的身份
+func CurrentIdentity(c *gin.Context) (*Identity, bool) {
+	v, ok := c.Get(ContextIdentityKey)
+	if !ok {
+		return nil, false
+	}
+	identity, ok := v.(*Identity)
+	return identity, ok
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultRolesPath 默认的角色配置文件
+const DefaultRolesPath = "./roles.json"
+
+// Role 角色，高等级的角色拥有低等级角色的全部权限
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // 查询余额、配置
+	RoleOperator Role = "operator" // 发起额度以内的转账
+	RoleApprover Role = "approver" // 发起超过额度的转账
+	RoleAdmin    Role = "admin"    // 管理角色
+)
+
+// roleLevels 角色等级
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleApprover: 3,
+	RoleAdmin:    4,
+}
+
+// ParseRole 解析角色名
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleLevels[role]; !ok {
+		return "", fmt.Errorf("未知的角色: %s", s)
+	}
+	return role, nil
+}
+
+// Allows 判断当前角色是否满足 required
+func (r Role) Allows(required Role) bool {
+	return roleLevels[r] >= roleLevels[required] && roleLevels[required] > 0
+}
+
+// rolesFile 角色配置文件的格式
+type rolesFile struct {
+	Addresses map[common.Address]Role `json:"addresses"`
+}
+
+// Roles 钱包地址 (SIWE 登录) 和角色的绑定关系，API key 的角色保存在 API key 文件中
+type Roles struct {
+	mu        sync.RWMutex
+	path      string
+	addresses map[common.Address]Role
+}
+
+// LoadRoles 读取角色配置，文件不存在时返回空配置 (所有请求都会被拒绝)
+func LoadRoles(path string) (*Roles, error) {
+	r := &Roles{
+		path:      path,
+		addresses: make(map[common.Address]Role),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f rolesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("角色配置 %s 格式有误: %w", path, err)
+	}
+	for addr, role := range f.Addresses {
+		parsed, err := ParseRole(string(role))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", addr.Hex(), err)
+		}
+		r.addresses[addr] = parsed
+	}
+	return r, nil
+}
+
+// Save 写回配置文件
+func (r *Roles) Save() error {
+	r.mu.RLock()
+	data, err := json.MarshalIndent(rolesFile{Addresses: r.addresses}, "", "  ")
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0600)
+}
+
+// AddressRole 查询钱包地址的角色
+func (r *Roles) AddressRole(address common.Address) (Role, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	role, ok := r.addresses[address]
+	return role, ok
+}
+
+// SetAddressRole 设置钱包地址的角色，role 为空时删除
+func (r *Roles) SetAddressRole(address common.Address, role Role) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if role == "" {
+		delete(r.addresses, address)
+		return
+	}
+	r.addresses[address] = role
+}
+
+// Addresses 返回所有地址的角色
+func (r *Roles) Addresses() map[common.Address]Role {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[common.Address]Role, len(r.addresses))
+	for addr, role := range r.addresses {
+		out[addr] = role
+	}
+	return out
+}
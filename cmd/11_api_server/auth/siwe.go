@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"learn-web3-go/cmd/11_api_server/request"
+	"learn-web3-go/cmd/11_api_server/response"
+	"learn-web3-go/pkg/signature"
+	"learn-web3-go/pkg/siwe"
+)
+
+// ContextSessionKey 会话在 gin.Context 中的 key
+const ContextSessionKey = "session"
+
+// SIWE Sign-In with Ethereum 登录接口
+type SIWE struct {
+	Store   *Store
+	Domain  string // 期望的域名，例如 localhost:8888
+	URI     string // 期望的 URI，为空时不检查
+	ChainID uint64
+
+	Verifier *signature.Verifier // 支持 Safe 等合约钱包登录，为空时只支持 EOA
+}
+
+// Nonce GET /auth/nonce 签发一次性 nonce
+func (h *SIWE) Nonce(c *gin.Context) {
+	nonce, err := h.Store.NewNonce()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, "生成 nonce 失败")
+		return
+	}
+	response.Success(c, gin.H{
+		"nonce":   nonce,
+		"domain":  h.Domain,
+		"uri":     h.URI,
+		"chainId": h.ChainID,
+	}, "获取成功")
+}
+
+// Verify POST /auth/verify 验证签名后的 SIWE 消息并返回会话 token
+func (h *SIWE) Verify(c *gin.Context) {
+	var req request.SiweVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, "无效的参数")
+		return
+	}
+	msg, err := siwe.ParseMessage(req.Message)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// 合约钱包的签名长度不固定，这里不限制为 65 字节
+	sig, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, "签名格式有误")
+		return
+	}
+
+	// nonce 必须是本服务签发且没有使用过的
+	if !h.Store.ConsumeNonce(msg.Nonce) {
+		response.Fail(c, http.StatusUnauthorized, siwe.ErrNonceMismatch.Error())
+		return
+	}
+	err = msg.Verify(c.Request.Context(), sig, siwe.VerifyOptions{
+		Domain:   h.Domain,
+		URI:      h.URI,
+		ChainID:  h.ChainID,
+		Nonce:    msg.Nonce,
+		Verifier: h.Verifier,
+	})
+	if err != nil {
+		log.Printf("SIWE 登录失败: %s %v", msg.Address.Hex(), err)
+		response.Fail(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	session, err := h.Store.CreateSession(msg.Address, msg.ChainID, msg.ExpirationTime)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, "创建会话失败")
+		return
+	}
+	log.Printf("SIWE 登录成功: %s", msg.Address.Hex())
+	response.Success(c, session, "登录成功")
+}
+
+// Logout POST /auth/logout 注销当前会话
+func (h *SIWE) Logout(c *gin.Context) {
+	h.Store.Revoke(bearerToken(c))
+	response.Success(c, nil, "已退出登录")
+}
+
+// CurrentSession 取出中间件写入的会话
+func CurrentSession(c *gin.Context) (*Session, bool) {
+	v, ok := c.Get(ContextSessionKey)
+	if !ok {
+		return nil, false
+	}
+	session, ok := v.(*Session)
+	return session, ok
+}
+
+// bearerToken 读取 Authorization 头中的 token
+func bearerToken(c *gin.Context) string {
+	return strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+}
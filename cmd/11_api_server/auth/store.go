@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/pkg/siwe"
+)
+
+// Session 登录会话
+type Session struct {
+	Token     string         `json:"token"`
+	Address   common.Address `json:"address"`
+	ChainID   uint64         `json:"chainId"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+}
+
+// Store 内存中的 nonce 和会话存储，服务重启后需要重新登录
+type Store struct {
+	mu         sync.Mutex
+	nonces     map[string]time.Time // nonce -> 过期时间
+	sessions   map[string]*Session  // token -> 会话
+	nonceTTL   time.Duration
+	sessionTTL time.Duration
+}
+
+// NewStore 创建存储
+func NewStore(nonceTTL, sessionTTL time.Duration) *Store {
+	return &Store{
+		nonces:     make(map[string]time.Time),
+		sessions:   make(map[string]*Session),
+		nonceTTL:   nonceTTL,
+		sessionTTL: sessionTTL,
+	}
+}
+
+// NewNonce 签发一个一次性的 nonce
+func (s *Store) NewNonce() (string, error) {
+	nonce, err := siwe.NewNonce()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanup()
+	s.nonces[nonce] = time.Now().Add(s.nonceTTL)
+	return nonce, nil
+}
+
+// ConsumeNonce 使用 nonce，每个 nonce 只能用一次
+func (s *Store) ConsumeNonce(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.nonces[nonce]
+	delete(s.nonces, nonce)
+	return ok && time.Now().Before(expiresAt)
+}
+
+// CreateSession 登录成功后创建会话，expiresAt 不为空时会话不会超过这个时间
+func (s *Store) CreateSession(address common.Address, chainID uint64, expiresAt *time.Time) (*Session, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	session := &Session{
+		Token:     hex.EncodeToString(b),
+		Address:   address,
+		ChainID:   chainID,
+		ExpiresAt: time.Now().Add(s.sessionTTL),
+	}
+	if expiresAt != nil && expiresAt.Before(session.ExpiresAt) {
+		session.ExpiresAt = *expiresAt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.Token] = session
+	return session, nil
+}
+
+// Session 根据 token 查找未过期的会话
+func (s *Store) Session(token string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[token]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(session.ExpiresAt) {
+		delete(s.sessions, token)
+		return nil, false
+	}
+	return session, true
+}
+
+// Revoke 注销会话
+func (s *Store) Revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+// cleanup 清理过期的 nonce 和会话，调用方需要持有锁
+func (s *Store) cleanup() {
+	now := time.Now()
+	for nonce, expiresAt := range s.nonces {
+		if now.After(expiresAt) {
+			delete(s.nonces, nonce)
+		}
+	}
+	for token, session := range s.sessions {
+		if now.After(session.ExpiresAt) {
+			delete(s.sessions, token)
+		}
+	}
+}
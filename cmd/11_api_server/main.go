@@ -1,24 +1,37 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gin-gonic/gin"
+	"learn-web3-go/cmd/11_api_server/auth"
 	"learn-web3-go/cmd/11_api_server/request"
 	"learn-web3-go/cmd/11_api_server/response"
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/apikey"
 	"learn-web3-go/pkg/chain"
 	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/permit"
+	"learn-web3-go/pkg/signature"
+	"learn-web3-go/pkg/token"
 	"log"
 	"math/big"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 var (
 	client    *ethclient.Client
 	adminUser *model.User // 服务器的“热钱包”账户
 	usdt      *erc20.ERC20
+	book      *addressbook.Book // 地址簿，用于展示标签和按名字转账
+	chainID   uint64
 )
 
 func main() {
@@ -29,38 +42,97 @@ func main() {
 	adminUser = model.NewUserFromEnv(client)
 	log.Printf("热钱包地址:%s", adminUser.Address.Hex())
 
+	// 加载地址簿
+	book = addressbook.LoadFromEnv()
+	chainID = chain.GetChainID(context.Background(), client).Uint64()
+
 	// 初始化 USDT
 	usdtAddr := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
 	usdt, err := erc20.NewERC20(usdtAddr, client)
 	if err != nil {
 		log.Fatal(err)
 	}
+	// 代币的精度和符号从合约读取，不再写死 USDT 的 6 位精度
+	resolver := token.NewResolver(client)
+	usdtMeta, err := resolver.Get(context.Background(), usdtAddr)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败 ", err)
+	}
+	if usdtMeta.DecimalsMissing {
+		log.Printf("warn: 代币没有 decimals()，按 %d 位精度处理", token.DefaultDecimals)
+	}
+
+	// SIWE 登录，域名和 URI 需要与前端页面的地址一致
+	siwe := &auth.SIWE{
+		Store:   auth.NewStore(5*time.Minute, 24*time.Hour),
+		Domain:  getEnv("SIWE_DOMAIN", "localhost:8888"),
+		URI:     getEnv("SIWE_URI", "http://localhost:8888"),
+		ChainID: chainID,
+		// 合约钱包 (EIP-1271) 需要查询链上合约验证签名
+		Verifier: signature.NewVerifier(client),
+	}
+
+	// 角色配置: 钱包地址 / API key -> 角色
+	roles, err := auth.LoadRoles(getEnv("RBAC_FILE", auth.DefaultRolesPath))
+	if err != nil {
+		log.Fatal("err: 加载角色配置失败 ", err)
+	}
+	// 首次部署时通过环境变量指定管理员，之后可以用 /admin/roles 分配其它角色
+	if admin := os.Getenv("RBAC_ADMIN_ADDRESS"); common.IsHexAddress(admin) {
+		roles.SetAddressRole(common.HexToAddress(admin), auth.RoleAdmin)
+	}
+	// 服务间调用的 API key (HMAC 签名)
+	apiKeys, err := apikey.LoadFromEnv()
+	if err != nil {
+		log.Fatal("err: 加载 API key 失败 ", err)
+	}
+	guard := &auth.Guard{
+		SIWE:    siwe,
+		Roles:   roles,
+		APIKeys: apikey.NewVerifier(apiKeys, apikey.DefaultWindow),
+		Audit:   auth.NewAuditLog(getEnv("AUDIT_LOG_FILE", auth.DefaultAuditPath)),
+	}
+
+	// operator 单笔转账的上限 (代币个数)，超过需要 approver
+	operatorLimit, err := usdtMeta.Parse(getEnv("OPERATOR_TRANSFER_LIMIT", "1000"))
+	if err != nil {
+		log.Fatal("err: OPERATOR_TRANSFER_LIMIT 格式有误 ", err)
+	}
+	// 兑现 permit 的转发合约 (permit 的 spender)，用 cmd/24_permit -action deploy-router 部署，operator 是热钱包
+	permitRouter := common.HexToAddress(os.Getenv("PERMIT_ROUTER_ADDR"))
 
 	r := gin.Default()
 
-	// 解决跨域
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*") // 允许任何来源
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	// 解决跨域，只允许白名单中的来源，多个来源用逗号分隔
+	r.Use(auth.CORS(strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:8888"), ",")))
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
+	// 登录页面
+	r.StaticFile("/", "./cmd/11_api_server/template/index.html")
+
+	// 登录接口
+	r.GET("/auth/nonce", siwe.Nonce)
+	r.POST("/auth/verify", siwe.Verify)
+	r.POST("/auth/logout", siwe.Logout)
+
+	// 以下接口需要先登录 (或使用 API key)，并按角色授权
+	r.Use(guard.Authenticate())
+
+	// 当前身份和角色
+	r.GET("/auth/me", guard.Require(auth.RoleViewer, ""), func(c *gin.Context) {
+		identity, _ := auth.CurrentIdentity(c)
+		response.Success(c, identity, "获取成功")
 	})
 
 	// 注册路由
 	// 获取 RPC_URL
-	r.GET("/getRpcUrl", func(c *gin.Context) {
+	r.GET("/getRpcUrl", guard.Require(auth.RoleViewer, auth.ScopeRead), func(c *gin.Context) {
 		response.Success(c, gin.H{
 			"rpcUrl": os.Getenv("RPC_URL"),
 		}, "获取成功")
 	})
 
 	// 获取钱包余额
-	r.GET("/balance", func(c *gin.Context) {
+	r.GET("/balance", guard.Require(auth.RoleViewer, auth.ScopeRead), func(c *gin.Context) {
 		addressStr := c.Query("address")
 		if !common.IsHexAddress(addressStr) {
 			response.Fail(c, http.StatusBadRequest, "无效的参数")
@@ -75,35 +147,51 @@ func main() {
 			return
 		}
 
-		// 格式化显示余额（Wei -> Human Readable）
-		humanBal := new(big.Float).Quo(new(big.Float).SetInt(bal), big.NewFloat(1e6))
-		val, _ := humanBal.Float64() // 转成浮点数
-
+		// 格式化显示余额（Wei -> Human Readable），用字符串返回避免浮点数丢失精度
 		response.Success(c, gin.H{
-			"balance": val,
-			"address": addressStr,
-			"symbol":  "USDT",
+			"balance":  usdtMeta.Amount(bal),
+			"raw":      bal.String(),
+			"decimals": usdtMeta.Decimals,
+			"address":  addressStr,
+			"label":    book.Name(chainID, targetAddr),
+			"symbol":   usdtMeta.Symbol,
 		}, "查询成功")
 	})
 
 	// 提现/转账
-	r.POST("/transfer", func(c *gin.Context) {
+	r.POST("/transfer", guard.Require(auth.RoleOperator, auth.ScopeTransfer), func(c *gin.Context) {
 		var req request.TransferRequest
 
-		err = c.ShouldBindJSON(&req)
-		if err != nil {
+		if err := c.ShouldBindJSON(&req); err != nil {
 			response.Fail(c, http.StatusBadRequest, "无效的参数")
 			return
 		}
-		if req.Amount <= 0 {
+		// 数值转换 (Human -> Wei)，按代币精度换算，小数位超过精度时拒绝
+		amount, err := req.Amount.Rescale(usdtMeta.Decimals)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if amount.Sign() <= 0 {
 			response.Fail(c, http.StatusBadRequest, "交易金额需要大于 0")
 			return
 		}
-		if !common.IsHexAddress(req.ToAddress) {
+		// 大额转账需要 approver
+		identity, _ := auth.CurrentIdentity(c)
+		if amount.Cmp(operatorLimit) > 0 && !identity.Role.Allows(auth.RoleApprover) {
+			guard.Deny(c, auth.RoleApprover, fmt.Sprintf("超过 %v %s 的转账需要 approver 角色", operatorLimit, usdtMeta.Symbol))
+			return
+		}
+		// 接收方可以是地址，也可以是地址簿中的名字
+		toAddress, err := book.Resolve(chainID, req.ToAddress)
+		if err != nil {
 			response.Fail(c, http.StatusBadRequest, "交易接收的地址无效")
 			return
 		}
 
+		// 记录发起转账的管理员
+		log.Printf("操作人: %s (%s)", identity.ID, identity.Role)
+
 		// 生成交易凭证 auth - 自动计算 gas 费用
 		auth, err := chain.NewAuth(client, adminUser)
 		if err != nil {
@@ -111,24 +199,203 @@ func main() {
 			return
 		}
 
-		// 数值转换 (Human -> Wei) USDT 精度 6: amount * 10^6
-		amountBig := big.NewInt(int64(req.Amount * 1000000))
-
-		// 发起交易
-		toAddress := common.HexToAddress(req.ToAddress)
 		// 开始转账
 		log.Println("正在广播交易中....")
-		tx, err := usdt.Transfer(auth, toAddress, amountBig)
+		// 发送前模拟执行，USDT 的 transfer 没有返回值，代币返回 false 时不会广播
+		tx, err := token.SafeTransfer(auth, client, usdtAddr, toAddress, amount.Int())
 		if err != nil {
 			response.Fail(c, http.StatusInternalServerError, "交易广播失败")
-			log.Fatal("交易广播失败", err.Error())
+			log.Println("交易广播失败", err.Error())
 			return
 		}
+		// 广播后在后台等待上链，核对 Transfer 日志，收取手续费或没有事件时记录告警
+		go verifyTransfer(tx, usdtAddr, auth.From, toAddress, amount, usdtMeta)
+		response.Success(c, gin.H{
+			"txHash":  tx.Hash().Hex(),
+			"to":      toAddress.Hex(),
+			"toLabel": book.Name(chainID, toAddress),
+			"amount":  amount,
+			"symbol":  usdtMeta.Symbol,
+		}, "交易已广播，等待上链...")
+	})
+
+	// 兑现用户签名的 permit，服务端支付 gas 把代币从用户转出
+	r.POST("/permit/redeem", guard.Require(auth.RoleOperator, auth.ScopePermit), func(c *gin.Context) {
+		var req request.PermitRedeemRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Fail(c, http.StatusBadRequest, "无效的参数")
+			return
+		}
+		p := req.Permit
+		if permitRouter == (common.Address{}) {
+			response.Fail(c, http.StatusInternalServerError, "未配置 PERMIT_ROUTER_ADDR")
+			return
+		}
+
+		toAddress := adminUser.Address
+		if req.ToAddress != "" {
+			resolved, err := book.Resolve(chainID, req.ToAddress)
+			if err != nil {
+				response.Fail(c, http.StatusBadRequest, "交易接收的地址无效")
+				return
+			}
+			toAddress = resolved
+		}
+		// permit 的代币不一定是 USDT，按 permit 中的代币地址查询精度
+		meta, err := resolver.Get(c.Request.Context(), p.Token)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, "查询代币信息失败")
+			return
+		}
+		amount := meta.Amount(p.Value.ToInt())
+		if req.Amount != nil {
+			if amount, err = req.Amount.Rescale(meta.Decimals); err != nil || amount.Sign() <= 0 {
+				response.Fail(c, http.StatusBadRequest, "交易金额无效")
+				return
+			}
+		}
+		// 与 /transfer 相同，大额转账需要 approver (按代币个数比较)
+		identity, _ := auth.CurrentIdentity(c)
+		if amount.Cmp(operatorLimit) > 0 && !identity.Role.Allows(auth.RoleApprover) {
+			guard.Deny(c, auth.RoleApprover, fmt.Sprintf("超过 %v 个代币的转账需要 approver 角色", operatorLimit))
+			return
+		}
+		log.Printf("操作人: %s (%s)", identity.ID, identity.Role)
+
+		permitToken, err := permit.NewToken(p.Token, new(big.Int).SetUint64(chainID), client)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, "连接代币合约失败")
+			return
+		}
+		txAuth, err := chain.NewAuth(client, adminUser)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, "生成签名失败")
+			return
+		}
+		// permit 和 transferFrom 在 Router 的同一笔交易中执行
+		tx, err := permitToken.Redeem(txAuth, permitRouter, p, toAddress, amount.Int())
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		go verifyTransfer(tx, p.Token, p.Owner, toAddress, amount, meta)
 		response.Success(c, gin.H{
 			"txHash": tx.Hash().Hex(),
+			"from":   p.Owner.Hex(),
+			"to":     toAddress.Hex(),
+			"amount": amount,
+			"symbol": meta.Symbol,
 		}, "交易已广播，等待上链...")
 	})
 
+	// 角色管理
+	r.GET("/admin/roles", guard.Require(auth.RoleAdmin, auth.ScopeAdmin), func(c *gin.Context) {
+		response.Success(c, roles.Addresses(), "获取成功")
+	})
+	r.POST("/admin/roles", guard.Require(auth.RoleAdmin, auth.ScopeAdmin), func(c *gin.Context) {
+		var req request.SetRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !common.IsHexAddress(req.Address) {
+			response.Fail(c, http.StatusBadRequest, "无效的参数")
+			return
+		}
+		var role auth.Role
+		if req.Role != "" {
+			parsed, err := auth.ParseRole(req.Role)
+			if err != nil {
+				response.Fail(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			role = parsed
+		}
+		roles.SetAddressRole(common.HexToAddress(req.Address), role)
+		if err := roles.Save(); err != nil {
+			response.Fail(c, http.StatusInternalServerError, "保存角色配置失败")
+			return
+		}
+		identity, _ := auth.CurrentIdentity(c)
+		log.Printf("%s 将 %s 的角色设置为 %q", identity.ID, req.Address, role)
+		response.Success(c, roles.Addresses(), "设置成功")
+	})
+
+	// API key 管理，secret 只在签发时返回一次
+	r.GET("/admin/apikeys", guard.Require(auth.RoleAdmin, auth.ScopeAdmin), func(c *gin.Context) {
+		keys := apiKeys.List()
+		for i := range keys {
+			keys[i].SigningKey = "" // 不返回签名密钥
+		}
+		response.Success(c, keys, "获取成功")
+	})
+	r.POST("/admin/apikeys", guard.Require(auth.RoleAdmin, auth.ScopeAdmin), func(c *gin.Context) {
+		var req request.IssueAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Fail(c, http.StatusBadRequest, "无效的参数")
+			return
+		}
+		role, err := auth.ParseRole(req.Role)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		scopes, err := auth.ParseScopes(req.Scopes)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		key, secret, err := apiKeys.Issue(req.Name, string(role), scopes, time.Duration(req.TTLHours)*time.Hour)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, "签发 API key 失败")
+			return
+		}
+		if err := apiKeys.Save(); err != nil {
+			response.Fail(c, http.StatusInternalServerError, "保存 API key 失败")
+			return
+		}
+		identity, _ := auth.CurrentIdentity(c)
+		log.Printf("%s 签发了 API key %s (%s, %s)", identity.ID, key.ID, key.Name, key.Role)
+		response.Success(c, gin.H{
+			"id":        key.ID,
+			"secret":    secret,
+			"role":      key.Role,
+			"scopes":    key.Scopes,
+			"expiresAt": key.ExpiresAt,
+		}, "签发成功，请立即保存 secret，之后无法再次查看")
+	})
+	r.DELETE("/admin/apikeys/:id", guard.Require(auth.RoleAdmin, auth.ScopeAdmin), func(c *gin.Context) {
+		if err := apiKeys.Revoke(c.Param("id")); err != nil {
+			response.Fail(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if err := apiKeys.Save(); err != nil {
+			response.Fail(c, http.StatusInternalServerError, "保存 API key 失败")
+			return
+		}
+		identity, _ := auth.CurrentIdentity(c)
+		log.Printf("%s 吊销了 API key %s", identity.ID, c.Param("id"))
+		response.Success(c, nil, "已吊销")
+	})
+
 	r.Run(":8888")
+}
+
+// getEnv 读取环境变量，为空时使用默认值
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
 
+// verifyTransfer 等待转账上链并核对实际到账的数量
+func verifyTransfer(tx *types.Transaction, tokenAddr, from, to common.Address, amount token.Amount, meta *token.Metadata) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	check, err := token.VerifyTransfer(ctx, client, tx, tokenAddr, from, to, amount.Int())
+	switch {
+	case check == nil:
+		log.Printf("warn: 等待交易 %s 上链失败: %v", tx.Hash().Hex(), err)
+	case err != nil:
+		log.Printf("warn: 交易 %s 转账异常: %v, 请求 %s %s, 实际到账 %s", tx.Hash().Hex(), err, amount, meta.Symbol, meta.Format(check.Received))
+	default:
+		log.Printf("交易 %s 已上链, 区块 %d, 到账 %s %s", tx.Hash().Hex(), check.Receipt.BlockNumber, amount, meta.Symbol)
+	}
 }
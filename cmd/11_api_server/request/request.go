@@ -1,6 +1,34 @@
 package request
 
+import (
+	"learn-web3-go/pkg/permit"
+	"learn-web3-go/pkg/token"
+)
+
 type TransferRequest struct {
-	ToAddress string  `json:"toAddress" binding:"required"`
-	Amount    float64 `json:"amount" binding:"required"`
+	ToAddress string       `json:"toAddress" binding:"required"` // 接收方地址，或地址簿中的名字
+	Amount    token.Amount `json:"amount"`                       // 代币数量，例如 "1.5"，小数位不能超过代币精度
+}
+
+type SiweVerifyRequest struct {
+	Message   string `json:"message" binding:"required"`   // 钱包签名的 EIP-4361 文本
+	Signature string `json:"signature" binding:"required"` // personal_sign 签名
+}
+
+type PermitRedeemRequest struct {
+	Permit    *permit.Permit `json:"permit" binding:"required"` // 用户签名的 EIP-2612 permit
+	ToAddress string         `json:"toAddress"`                 // 收款地址或地址簿中的名字，为空时转给热钱包
+	Amount    *token.Amount  `json:"amount"`                    // 代币数量，例如 "1.5"，为空时使用 permit 的额度
+}
+
+type SetRoleRequest struct {
+	Address string `json:"address" binding:"required"`
+	Role    string `json:"role"` // viewer | operator | approver | admin，为空时删除
+}
+
+type IssueAPIKeyRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Role     string   `json:"role" binding:"required"`   // viewer | operator | approver | admin
+	Scopes   []string `json:"scopes" binding:"required"` // read | transfer | permit | admin | *
+	TTLHours int      `json:"ttlHours"`                  // 有效期 (小时)，0 表示永不过期
 }
@@ -9,8 +9,8 @@ import (
 	"github.com/joho/godotenv"
 	"learn-web3-go/contracts/erc20"
 	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/token"
 	"log"
-	"math/big"
 	"os"
 )
 
@@ -38,6 +38,12 @@ func main() {
 	// 过滤条件,只关心 USDT 的合约事件
 	contractAddr := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
 
+	// 代币的精度和符号从合约读取
+	meta, err := token.NewResolver(client).Get(ctx, contractAddr)
+	if err != nil {
+		log.Fatal("查询代币信息失败:", err)
+	}
+
 	// 只看 Transfer 事件
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{contractAddr},
@@ -78,8 +84,7 @@ func main() {
 			fmt.Printf("   到 (To)  : %s\n", event.To.Hex())
 
 			// 格式化金额
-			amount := new(big.Float).Quo(new(big.Float).SetInt(event.Value), big.NewFloat(1e6))
-			fmt.Printf("   金额     : %.2f USDT\n", amount)
+			fmt.Printf("   金额     : %s %s\n", meta.Format(event.Value), meta.Symbol)
 			fmt.Println("------------------------------------------------")
 
 		}
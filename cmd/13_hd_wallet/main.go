@@ -1,52 +1,49 @@
 package main
 
 import (
-	"crypto/ecdsa"
+	"flag"
 	"fmt"
-	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/tyler-smith/go-bip39"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/wallet"
 	"log"
 )
 
 func main() {
-	// 生成助记词 (Mnemonic)
+	// 命令行参数
+	file := flag.String("file", "./tmp/vault.json", "保存助记词的保险箱文件")
+	bits := flag.Int("bits", 128, "熵长度: 128 (12 个单词) 或 256 (24 个单词)")
+	count := flag.Int("count", 3, "显示前几个账户的地址")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	// 生成助记词 (Mnemonic) 并加密写入保险箱，助记词不会输出到终端
 	// 128 位随机数 -> 12 个单词
 	// 256 位随机数 -> 24 个单词
-	entropy, err := bip39.NewEntropy(128)
+	_, mnemonic, err := wallet.CreateVaultFile(*file, nil, *bits, "VAULT_PASSWORD")
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("err: 创建保险箱失败 ", err)
 	}
+	defer wallet.ZeroBytes(mnemonic)
 
-	// 将随机数查表转换成单词
-	mnemonic, _ := bip39.NewMnemonic(entropy)
 	// 助记词 (Mnemonic) -> 种子 (Seed)
-	seed := bip39.NewSeed(mnemonic, "")
-	// 种子 (Seed) -> 私钥 (Private Key)
-	// seed 是 64 字节， 私钥只需要 32 字节
-	privateKey, err := crypto.ToECDSA(seed[:32])
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 私钥 (Private Key) -> 公钥 (Public Key) -> 地址 (Address)
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("err: 推导公钥失败")
+	seed := wallet.MnemonicToSeed(mnemonic, nil)
+	defer wallet.ZeroBytes(seed)
+
+	// 种子 (Seed) -> 按 BIP-44 路径推导私钥 (Private Key) -> 地址 (Address)
+	// 同一个助记词可以推导出任意多个账户，只需要改变路径最后的序号
+	fmt.Println("钱包的前几个账户:")
+	for i := 0; i < *count; i++ {
+		path := fmt.Sprintf("m/44'/60'/0'/0/%d", i)
+		key, address, err := wallet.DeriveAddress(seed, path)
+		if err != nil {
+			log.Fatal("err: 推导账户失败 ", err)
+		}
+		wallet.ZeroKey(key)
+		fmt.Printf("   %s  %s\n", path, address.Hex())
 	}
 
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	// 去掉 "0x" 前缀
-	privHex := hexutil.Encode(crypto.FromECDSA(privateKey))[2:]
-
-	// 输出
-	fmt.Println("最终的钱包详情如下:")
-	fmt.Printf("   私钥: %s \n", privHex)
-	fmt.Printf("   地址: %s \n", address.Hex())
-	fmt.Printf("   助记词: %s \n", mnemonic)
-	fmt.Printf("   种子: %x \n", seed)
 	fmt.Println("--------------------")
-
+	fmt.Printf("助记词已加密保存到 %s\n", *file)
+	fmt.Println("推导私钥请使用 cmd/18_mnemonic_vault -action derive, 备份请使用 cmd/19_shamir_backup")
 }
@@ -10,15 +10,16 @@ import (
 	"github.com/joho/godotenv"
 	ens "github.com/wealdtech/go-ens/v3"
 	"log"
-	"math/big"
 	"os"
 
 	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
 	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/token"
 )
 
-// WhaleThreshold USDT 精度是 6，所以是 10000 * 10^6
-var WhaleThreshold = new(big.Int).Mul(big.NewInt(10000), big.NewInt(1e6))
+// WhaleThreshold 大额交易的阈值 (代币个数)，按合约的精度换算成最小单位
+const WhaleThreshold = "10000"
 
 func main() {
 	err := godotenv.Load()
@@ -31,8 +32,20 @@ func main() {
 	client := chain.InitWSClient()
 	fmt.Println("监听器启动... ")
 
+	// 加载地址簿，优先展示本地标签
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(context.Background(), client).Uint64()
+
 	// 准备过滤条件
 	usdtAddr := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
+	meta, err := token.NewResolver(client).Get(context.Background(), usdtAddr)
+	if err != nil {
+		log.Fatal("查询代币信息失败:", err)
+	}
+	threshold, err := meta.Parse(WhaleThreshold)
+	if err != nil {
+		log.Fatal(err)
+	}
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{usdtAddr},
 	}
@@ -61,20 +74,17 @@ func main() {
 			}
 
 			// 筛选大额交易
-			// 如果 event.Value < 10000_000000，就跳过
-			if event.Value.Cmp(WhaleThreshold) < 0 {
+			// 如果 event.Value < 10000 * 10^decimals，就跳过
+			if meta.Amount(event.Value).Cmp(threshold) < 0 {
 				continue
 			}
 
-			// 格式化金额
-			amountFloat, _ := new(big.Float).Quo(new(big.Float).SetInt(event.Value), big.NewFloat(1e6)).Float64()
-
-			// 尝试 ENS 反向解析
-			fromName := getEnsName(client, event.From)
-			toName := getEnsName(client, event.To)
+			// 地址簿标签优先，其次尝试 ENS 反向解析
+			fromName := getName(client, book, chainID, event.From)
+			toName := getName(client, book, chainID, event.To)
 
 			// 触发报警
-			fmt.Printf("金额: %.2f USDT\n", amountFloat)
+			fmt.Printf("金额: %s %s\n", meta.Format(event.Value), meta.Symbol)
 			fmt.Printf("发送方: %s (%s)\n", fromName, event.From.Hex())
 			fmt.Printf("接收方: %s (%s)\n", toName, event.To.Hex())
 			fmt.Printf("TxHash: %s\n", vLog.TxHash.Hex())
@@ -83,8 +93,11 @@ func main() {
 	}
 }
 
-// getEnsName 尝试获取 ENS 域名
-func getEnsName(client *ethclient.Client, addr common.Address) string {
+// getName 先查地址簿，查不到再尝试获取 ENS 域名
+func getName(client *ethclient.Client, book *addressbook.Book, chainID uint64, addr common.Address) string {
+	if name := book.Name(chainID, addr); name != "" {
+		return name
+	}
 
 	name, err := ens.ReverseResolve(client, addr)
 	if err != nil {
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/wallet"
+	"log"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"time"
+)
+
+func main() {
+	// 命令行参数
+	prefix := flag.String("prefix", "", "地址前缀, 例如 dead")
+	suffix := flag.String("suffix", "", "地址后缀, 例如 beef")
+	pattern := flag.String("regex", "", "自定义正则, 匹配去掉 0x 的地址")
+	caseSensitive := flag.Bool("case", false, "按 EIP-55 校验和大小写匹配")
+	workers := flag.Int("workers", runtime.NumCPU(), "并发协程数")
+	ksDir := flag.String("keystore", "./tmp/keystore", "keystore 存储目录")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	// keystore 密码只从环境变量读取，避免出现在 shell 历史里
+	password := os.Getenv("KEYSTORE_PASSWORD")
+	if password == "" {
+		log.Fatal("err: 缺少 KEYSTORE_PASSWORD")
+		return
+	}
+
+	opts := wallet.VanityOptions{
+		Prefix:        *prefix,
+		Suffix:        *suffix,
+		CaseSensitive: *caseSensitive,
+		Workers:       *workers,
+	}
+	if *pattern != "" {
+		re, err := regexp.Compile(*pattern)
+		if err != nil {
+			log.Fatal("err: 正则格式有误 ", err)
+			return
+		}
+		opts.Regex = re
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatal("err: ", err)
+		return
+	}
+
+	if d := opts.Difficulty(); d > 0 {
+		fmt.Printf("开始搜索, 协程数: %d, 平均需要尝试: %.0f 次\n", *workers, d)
+	} else {
+		fmt.Printf("开始搜索, 协程数: %d, 正则模式无法估算难度\n", *workers)
+	}
+
+	// Ctrl+C 中断搜索
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := wallet.GenerateVanity(ctx, opts, 2*time.Second, func(p wallet.VanityProgress) {
+		if p.ETA > 0 {
+			fmt.Printf("已尝试: %d, 速度: %.0f 个/秒, 命中概率: %.2f%%, 预计耗时: %s\n",
+				p.Attempts, p.Rate, p.Probability*100, p.ETA.Round(time.Second))
+		} else {
+			fmt.Printf("已尝试: %d, 速度: %.0f 个/秒\n", p.Attempts, p.Rate)
+		}
+	})
+	if err != nil {
+		log.Fatal("err: 搜索已中断 ", err)
+		return
+	}
+	defer wallet.ZeroKey(result.PrivateKey)
+
+	// 私钥直接加密写入 keystore，不在终端输出
+	account, err := wallet.SaveToKeystore(*ksDir, password, result.PrivateKey)
+	if err != nil {
+		log.Fatal("err: 写入 keystore 失败 ", err)
+		return
+	}
+
+	fmt.Println("找到靓号地址:")
+	fmt.Printf("   地址: %s\n", result.Address.Hex())
+	fmt.Printf("   尝试次数: %d, 耗时: %s\n", result.Attempts, result.Elapsed.Round(time.Millisecond))
+	fmt.Printf("   keystore 文件: %s\n", account.URL.Path)
+}
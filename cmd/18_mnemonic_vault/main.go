@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/wallet"
+	"log"
+	"os"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "address", "操作: create | address | derive | rekey")
+	file := flag.String("file", "./tmp/vault.json", "保险箱文件路径")
+	path := flag.String("path", wallet.DefaultDerivationPath, "BIP-32 推导路径")
+	bits := flag.Int("bits", 128, "新助记词的熵长度: 128 (12 个单词) 或 256 (24 个单词)")
+	ksDir := flag.String("keystore", "", "推导出的私钥写入的 keystore 目录, 为空则只显示地址")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	switch *action {
+	case "create":
+		create(*file, *bits)
+	case "address":
+		vault, err := wallet.LoadVault(*file)
+		if err != nil {
+			log.Fatal("err: 读取保险箱失败 ", err)
+		}
+		fmt.Printf("保险箱版本: %d, 默认地址: %s\n", vault.Header.Version, vault.Address().Hex())
+	case "derive":
+		derive(*file, *path, *ksDir)
+	case "rekey":
+		rekey(*file)
+	default:
+		log.Fatal("err: 未知的操作 ", *action)
+	}
+}
+
+// create 创建保险箱，MNEMONIC 存在时导入已有的助记词，否则生成新的助记词
+func create(file string, bits int) {
+	imported := []byte(os.Getenv("MNEMONIC"))
+	defer wallet.ZeroBytes(imported)
+
+	vault, mnemonic, err := wallet.CreateVaultFile(file, imported, bits, "VAULT_PASSWORD")
+	if err != nil {
+		log.Fatal("err: 创建保险箱失败 ", err)
+	}
+	wallet.ZeroBytes(mnemonic)
+
+	// 助记词不会输出，需要备份时请使用 Shamir 分片
+	fmt.Println("保险箱创建成功:")
+	fmt.Printf("   文件: %s\n", file)
+	fmt.Printf("   默认地址: %s\n", vault.Address().Hex())
+}
+
+// derive 解锁保险箱并按路径推导账户
+func derive(file, path, ksDir string) {
+	vault, err := wallet.LoadVault(file)
+	if err != nil {
+		log.Fatal("err: 读取保险箱失败 ", err)
+	}
+	password, err := wallet.ReadPassword("VAULT_PASSWORD")
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	defer wallet.ZeroBytes(password)
+
+	unlocked, err := vault.Unlock(password)
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	defer unlocked.Close()
+
+	passphrase := []byte(os.Getenv("MNEMONIC_PASSPHRASE"))
+	defer wallet.ZeroBytes(passphrase)
+
+	key, address, err := unlocked.Derive(path, passphrase)
+	if err != nil {
+		log.Fatal("err: 推导账户失败 ", err)
+	}
+	defer wallet.ZeroKey(key)
+
+	fmt.Printf("路径: %s\n", path)
+	fmt.Printf("地址: %s\n", address.Hex())
+
+	if ksDir == "" {
+		return
+	}
+	// 私钥以 keystore 的形式导出
+	ksPassword := os.Getenv("KEYSTORE_PASSWORD")
+	account, err := wallet.SaveToKeystore(ksDir, ksPassword, key)
+	if err != nil {
+		log.Fatal("err: 写入 keystore 失败 ", err)
+	}
+	fmt.Printf("keystore 文件: %s\n", account.URL.Path)
+}
+
+// rekey 使用 VAULT_PASSWORD 解锁，并改用 VAULT_NEW_PASSWORD 重新加密
+func rekey(file string) {
+	vault, err := wallet.LoadVault(file)
+	if err != nil {
+		log.Fatal("err: 读取保险箱失败 ", err)
+	}
+	oldPassword, err := wallet.ReadPassword("VAULT_PASSWORD")
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	defer wallet.ZeroBytes(oldPassword)
+	newPassword, err := wallet.ReadPassword("VAULT_NEW_PASSWORD")
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	defer wallet.ZeroBytes(newPassword)
+
+	if err := vault.Rekey(oldPassword, newPassword); err != nil {
+		log.Fatal("err: 更换密码失败 ", err)
+	}
+	if err := vault.Save(file); err != nil {
+		log.Fatal("err: 保存保险箱失败 ", err)
+	}
+	fmt.Println("保险箱密码已更换")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/joho/godotenv"
+	"github.com/tyler-smith/go-bip39"
+	"learn-web3-go/pkg/wallet"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "split", "操作: split | combine")
+	keyFile := flag.String("keyfile", "", "要拆分的 keystore 文件 (KEYSTORE_PASSWORD 解锁)")
+	vaultFile := flag.String("vault", "", "要拆分的助记词保险箱文件 (VAULT_PASSWORD 解锁)")
+	threshold := flag.Int("m", 2, "还原需要的分片数量")
+	total := flag.Int("n", 3, "分片总数")
+	outDir := flag.String("out", "./tmp/shares", "分片输出目录 (split) / 还原结果输出目录 (combine)")
+	shareFiles := flag.String("shares", "", "逗号分隔的分片文件 (combine)")
+	expected := flag.String("address", "", "期望还原出的地址 (combine)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	switch *action {
+	case "split":
+		split(*keyFile, *vaultFile, *threshold, *total, *outDir)
+	case "combine":
+		combine(*shareFiles, *expected, *outDir)
+	default:
+		log.Fatal("err: 未知的操作 ", *action)
+	}
+}
+
+// split 读取私钥或助记词并拆分成 N 个分片，每个分片写入单独的文件
+func split(keyFile, vaultFile string, threshold, total int, outDir string) {
+	var (
+		kind    string
+		secret  []byte
+		address common.Address
+	)
+
+	switch {
+	case keyFile != "":
+		// 解密 keystore 拿到私钥
+		jsonBytes, err := os.ReadFile(keyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		key, err := keystore.DecryptKey(jsonBytes, os.Getenv("KEYSTORE_PASSWORD"))
+		if err != nil {
+			log.Fatal("密码错误或者文件已经损坏了", err)
+		}
+		kind, secret, address = wallet.ShareKindKey, crypto.FromECDSA(key.PrivateKey), key.Address
+		wallet.ZeroKey(key.PrivateKey)
+
+	case vaultFile != "":
+		// 解锁保险箱，把助记词还原成熵再拆分
+		vault, err := wallet.LoadVault(vaultFile)
+		if err != nil {
+			log.Fatal("err: 读取保险箱失败 ", err)
+		}
+		password, err := wallet.ReadPassword("VAULT_PASSWORD")
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		unlocked, err := vault.Unlock(password)
+		wallet.ZeroBytes(password)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		mnemonic := unlocked.Mnemonic()
+		unlocked.Close()
+		entropy, err := bip39.EntropyFromMnemonic(string(mnemonic))
+		wallet.ZeroBytes(mnemonic)
+		if err != nil {
+			log.Fatal("err: 助记词无效 ", err)
+		}
+		kind, secret, address = wallet.ShareKindEntropy, entropy, vault.Address()
+
+	default:
+		log.Fatal("err: 需要指定 -keyfile 或 -vault")
+	}
+	defer wallet.ZeroBytes(secret)
+
+	shares, err := wallet.SplitSecret(kind, secret, threshold, total)
+	if err != nil {
+		log.Fatal("err: 拆分失败 ", err)
+	}
+
+	if err := os.MkdirAll(outDir, 0700); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("地址 %s 已拆分为 %d 份，任意 %d 份可以还原:\n", address.Hex(), total, threshold)
+	for _, share := range shares {
+		name := filepath.Join(outDir, fmt.Sprintf("%s-share-%d.txt", strings.ToLower(address.Hex()), share.Index))
+		if err := os.WriteFile(name, []byte(share.String()+"\n"), 0600); err != nil {
+			log.Fatal(err)
+		}
+		wallet.ZeroBytes(share.Value)
+		fmt.Printf("   %s\n", name)
+	}
+	fmt.Println("请把分片分别交给不同的保管人，并删除本机上的分片文件")
+}
+
+// combine 读取分片并还原，私钥写入 keystore，助记词写入保险箱
+func combine(shareFiles, expected, outDir string) {
+	if !common.IsHexAddress(expected) {
+		log.Fatal("err: 需要通过 -address 指定期望的地址")
+	}
+	expectedAddr := common.HexToAddress(expected)
+
+	var shares []wallet.Share
+	for _, name := range strings.Split(shareFiles, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		text, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		share, err := wallet.ParseShare(string(text))
+		if err != nil {
+			log.Fatalf("err: 分片 %s 无效: %v", name, err)
+		}
+		shares = append(shares, share)
+	}
+	if len(shares) == 0 {
+		log.Fatal("err: 需要通过 -shares 指定分片文件")
+	}
+
+	switch shares[0].Kind {
+	case wallet.ShareKindKey:
+		key, err := wallet.RecoverKey(shares, expectedAddr)
+		if err != nil {
+			log.Fatal("err: 还原失败 ", err)
+		}
+		defer wallet.ZeroKey(key)
+		account, err := wallet.SaveToKeystore(filepath.Join(outDir, "keystore"), os.Getenv("KEYSTORE_PASSWORD"), key)
+		if err != nil {
+			log.Fatal("err: 写入 keystore 失败 ", err)
+		}
+		fmt.Printf("私钥还原成功，地址: %s\n", account.Address.Hex())
+		fmt.Printf("keystore 文件: %s\n", account.URL.Path)
+
+	case wallet.ShareKindEntropy:
+		mnemonic, err := wallet.RecoverMnemonic(shares, expectedAddr)
+		if err != nil {
+			log.Fatal("err: 还原失败 ", err)
+		}
+		defer wallet.ZeroBytes(mnemonic)
+		password, err := wallet.ReadPassword("VAULT_PASSWORD")
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		defer wallet.ZeroBytes(password)
+		vault, err := wallet.NewVault(mnemonic, password)
+		if err != nil {
+			log.Fatal("err: 创建保险箱失败 ", err)
+		}
+		if err := os.MkdirAll(outDir, 0700); err != nil {
+			log.Fatal(err)
+		}
+		file := filepath.Join(outDir, "recovered-vault.json")
+		if err := vault.Save(file); err != nil {
+			log.Fatal("err: 保存保险箱失败 ", err)
+		}
+		fmt.Printf("助记词还原成功，地址: %s\n", vault.Address().Hex())
+		fmt.Printf("保险箱文件: %s\n", file)
+
+	default:
+		log.Fatal("err: 未知的分片类型 ", shares[0].Kind)
+	}
+}
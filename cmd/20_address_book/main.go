@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/addressbook"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "list", "操作: list | add | remove | import | export")
+	name := flag.String("name", "", "名字")
+	address := flag.String("address", "", "地址")
+	tags := flag.String("tags", "", "逗号分隔的标签, 例如 exchange,hot-wallet")
+	chains := flag.String("chains", "", "逗号分隔的链 ID, 为空表示所有链通用 (remove 时只删除该链的记录)")
+	notes := flag.String("notes", "", "备注")
+	file := flag.String("file", "", "导入/导出的文件, 按扩展名区分 .csv 或 .json")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	// 这里会写回文件，格式有误时必须退出，不能用空地址簿覆盖原文件
+	book, err := addressbook.Load(addressbook.PathFromEnv())
+	if err != nil {
+		log.Fatal("err: 加载地址簿失败 ", err)
+	}
+
+	switch *action {
+	case "list":
+		for _, e := range book.Entries() {
+			fmt.Printf("%-20s %s  tags=%v chains=%v %s\n", e.Name, e.Address.Hex(), e.Tags, e.ChainIDs, e.Notes)
+		}
+		return
+
+	case "add":
+		if !common.IsHexAddress(*address) {
+			log.Fatal("err: 无效的地址 ", *address)
+		}
+		entry := addressbook.Entry{
+			Name:     *name,
+			Address:  common.HexToAddress(*address),
+			Tags:     splitComma(*tags),
+			ChainIDs: parseChains(*chains),
+			Notes:    *notes,
+		}
+		if err := book.Add(entry); err != nil {
+			log.Fatal("err: 添加失败 ", err)
+		}
+		fmt.Printf("已添加: %s\n", book.Label(0, entry.Address))
+
+	case "remove":
+		// 指定 -chains 时只删除适用于该链的同名记录
+		ids := parseChains(*chains)
+		if len(ids) > 1 {
+			log.Fatal("err: 删除时 -chains 只能指定一个链 ID")
+		}
+		var chainID uint64
+		if len(ids) == 1 {
+			chainID = ids[0]
+		}
+		n := book.Remove(*name, chainID)
+		if n == 0 {
+			log.Fatal("err: 地址簿中找不到 ", *name)
+		}
+		fmt.Printf("已删除: %s (%d 条)\n", *name, n)
+
+	case "import":
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if isCSV(*file) {
+			err = book.ImportCSV(f)
+		} else {
+			err = book.ImportJSON(f)
+		}
+		if err != nil {
+			log.Fatal("err: 导入失败 ", err)
+		}
+		fmt.Printf("导入成功, 当前共 %d 条记录\n", len(book.Entries()))
+
+	case "export":
+		f, err := os.Create(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if isCSV(*file) {
+			err = book.ExportCSV(f)
+		} else {
+			err = book.ExportJSON(f)
+		}
+		if err != nil {
+			log.Fatal("err: 导出失败 ", err)
+		}
+		fmt.Printf("已导出到 %s\n", *file)
+		return
+
+	default:
+		log.Fatal("err: 未知的操作 ", *action)
+	}
+
+	// 修改过的地址簿写回文件
+	if err := book.Save(); err != nil {
+		log.Fatal("err: 保存地址簿失败 ", err)
+	}
+}
+
+// splitComma 拆分逗号分隔的参数
+func splitComma(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseChains 解析逗号分隔的链 ID
+func parseChains(s string) []uint64 {
+	var ids []uint64
+	for _, item := range splitComma(s) {
+		id, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			log.Fatal("err: 无效的链 ID ", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// isCSV 根据扩展名判断是否是 CSV 文件
+func isCSV(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".csv")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/multisig"
+	"log"
+	"math/big"
+	"os"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "info", "操作: info | propose | sign | approve | exec")
+	file := flag.String("proposal", "./tmp/safe-proposal.json", "提案文件")
+	to := flag.String("to", "", "交易目标地址 (propose)")
+	value := flag.String("value", "0", "转账的 ETH 数量, 单位 Wei (propose)")
+	data := flag.String("data", "0x", "调用数据 (propose)")
+	flag.Parse()
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+
+	if !common.IsHexAddress(os.Getenv("SAFE_ADDRESS")) {
+		log.Fatal("err: 缺少 SAFE_ADDRESS")
+	}
+	safeAddr := common.HexToAddress(os.Getenv("SAFE_ADDRESS"))
+	wallet, err := multisig.NewSafe(safeAddr, client)
+	if err != nil {
+		log.Fatal("err: 连接 Safe 失败 ", err)
+	}
+
+	switch *action {
+	case "info":
+		owners, err := wallet.Owners(opts)
+		if err != nil {
+			log.Fatal("err: 读取 owner 失败 ", err)
+		}
+		threshold, _ := wallet.Threshold(opts)
+		nonce, _ := wallet.Nonce(opts)
+		version, _ := wallet.Version(opts)
+		fmt.Printf("Safe: %s (v%s)\n", safeAddr.Hex(), version)
+		fmt.Printf("   门限: %s / %d\n", threshold, len(owners))
+		fmt.Printf("   Nonce: %s\n", nonce)
+		for _, owner := range owners {
+			fmt.Printf("   Owner: %s\n", owner.Hex())
+		}
+
+	case "propose":
+		if !common.IsHexAddress(*to) {
+			log.Fatal("err: 无效的目标地址 ", *to)
+		}
+		amount, ok := new(big.Int).SetString(*value, 10)
+		if !ok {
+			log.Fatal("err: 无效的金额 ", *value)
+		}
+		callData, err := hexutil.Decode(*data)
+		if err != nil {
+			log.Fatal("err: 无效的调用数据 ", err)
+		}
+		tx, err := wallet.NewTx(opts, common.HexToAddress(*to), amount, callData)
+		if err != nil {
+			log.Fatal("err: 读取 nonce 失败 ", err)
+		}
+		proposal := multisig.NewProposal(chain.GetChainID(ctx, client), safeAddr, tx)
+
+		// 本地计算的哈希和合约计算的哈希必须一致
+		onchain, err := wallet.TxHash(opts, tx)
+		if err != nil {
+			log.Fatal("err: 读取交易哈希失败 ", err)
+		}
+		if onchain != proposal.SafeTxHash {
+			log.Fatalf("err: 哈希不一致, 本地 %s, 合约 %s", proposal.SafeTxHash.Hex(), onchain.Hex())
+		}
+		if err := proposal.Save(*file); err != nil {
+			log.Fatal("err: 保存提案失败 ", err)
+		}
+		fmt.Printf("提案已创建: %s\n", *file)
+		fmt.Printf("   safeTxHash: %s\n", proposal.SafeTxHash.Hex())
+
+	case "sign":
+		proposal := loadProposal(*file)
+		user := model.NewUserFromEnv(client)
+		if err := proposal.Sign(user); err != nil {
+			log.Fatal("err: 签名失败 ", err)
+		}
+		if err := proposal.Save(*file); err != nil {
+			log.Fatal("err: 保存提案失败 ", err)
+		}
+		fmt.Printf("%s 已签名, 当前签名数: %d\n", user.Address.Hex(), len(proposal.Signatures))
+
+	case "approve":
+		proposal := loadProposal(*file)
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		tx, err := wallet.ApproveHash(auth, proposal.SafeTxHash)
+		if err != nil {
+			log.Fatal("err: approveHash 失败 ", err)
+		}
+		if _, err := bind.WaitMined(ctx, client, tx); err != nil {
+			log.Fatal("err: 等待上链失败 ", err)
+		}
+		// 记录为预先批准类型的签名
+		if err := proposal.AddSignature(multisig.ApprovedHashSignature(user.Address)); err != nil {
+			log.Fatal(err)
+		}
+		if err := proposal.Save(*file); err != nil {
+			log.Fatal("err: 保存提案失败 ", err)
+		}
+		fmt.Printf("%s 已在链上批准, 交易哈希: %s\n", user.Address.Hex(), tx.Hash().Hex())
+
+	case "exec":
+		proposal := loadProposal(*file)
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		auth.Context = ctx
+		tx, err := wallet.Exec(auth, proposal.Tx, proposal.Signatures)
+		if err != nil {
+			log.Fatal("err: 执行失败 ", err)
+		}
+		fmt.Printf("execTransaction 已广播: %s\n", tx.Hash().Hex())
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		if err != nil {
+			log.Fatal("err: 等待上链失败 ", err)
+		}
+		ok, err := wallet.ExecResult(receipt)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		if ok {
+			fmt.Println("Safe 交易执行成功")
+		} else {
+			fmt.Println("Safe 交易执行失败 (ExecutionFailure)")
+		}
+
+	default:
+		log.Fatal("err: 未知的操作 ", *action)
+	}
+}
+
+// loadProposal 读取提案并确认属于当前 Safe
+func loadProposal(file string) *multisig.Proposal {
+	proposal, err := multisig.LoadProposal(file)
+	if err != nil {
+		log.Fatal("err: 读取提案失败 ", err)
+	}
+	if proposal.Safe != common.HexToAddress(os.Getenv("SAFE_ADDRESS")) {
+		log.Fatal("err: 提案不属于当前的 Safe ", proposal.Safe.Hex())
+	}
+	return proposal
+}
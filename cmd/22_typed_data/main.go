@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/signature"
+	"log"
+	"os"
+)
+
+func main() {
+	// 命令行参数
+	file := flag.String("file", "./cmd/22_typed_data/mail.json", "eth_signTypedData_v4 格式的 JSON 文件")
+	sigHex := flag.String("sig", "", "要验证的签名, 为空时使用 PRIVATE_KEY 签名")
+	address := flag.String("address", "", "期望的签名者地址 (验证时使用)")
+	flag.Parse()
+
+	// 读取并解析结构化数据
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	td, err := signature.ParseTypedData(data)
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+
+	domainSeparator, err := signature.DomainSeparator(td)
+	if err != nil {
+		log.Fatal("err: 计算域分隔符失败 ", err)
+	}
+	structHash, err := signature.StructHash(td)
+	if err != nil {
+		log.Fatal("err: 计算结构体哈希失败 ", err)
+	}
+	hash, err := signature.TypedDataHash(td)
+	if err != nil {
+		log.Fatal("err: 计算签名哈希失败 ", err)
+	}
+	fmt.Printf("primaryType:     %s\n", td.PrimaryType)
+	fmt.Printf("domainSeparator: %s\n", domainSeparator.Hex())
+	fmt.Printf("structHash:      %s\n", structHash.Hex())
+	fmt.Printf("签名哈希:        %s\n", hash.Hex())
+
+	// 验证外部 (例如 MetaMask) 提供的签名
+	if *sigHex != "" {
+		sig, err := signature.DecodeSignature(*sigHex)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		signer, err := signature.RecoverTypedData(td, sig)
+		if err != nil {
+			log.Fatal("err: 恢复签名者失败 ", err)
+		}
+		fmt.Printf("签名者: %s\n", signer.Hex())
+		if common.IsHexAddress(*address) {
+			fmt.Printf("与期望地址一致: %v\n", signer == common.HexToAddress(*address))
+		}
+		return
+	}
+
+	// 使用 .env 中的私钥签名
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("err: 找不到 .env 文件")
+	}
+	user := model.NewUserFromEnv(nil)
+	sig, err := signature.SignTypedData(user, td)
+	if err != nil {
+		log.Fatal("err: 签名失败 ", err)
+	}
+	fmt.Printf("签名者: %s\n", user.Address.Hex())
+	fmt.Printf("签名:   %s\n", hexutil.Encode(sig))
+
+	ok, err := signature.VerifyTypedData(user.Address, td, sig)
+	if err != nil {
+		log.Fatal("err: 验证失败 ", err)
+	}
+	fmt.Printf("签名验证通过: %v\n", ok)
+}
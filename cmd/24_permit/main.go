@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/permit"
+	"learn-web3-go/pkg/token"
+	"log"
+	"math/big"
+	"os"
+	"time"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "info", "操作: info | deploy-router | sign | verify | redeem")
+	file := flag.String("permit", "./tmp/permit.json", "permit 文件")
+	spender := flag.String("spender", "", "被授权的地址, 为空时使用 PERMIT_ROUTER_ADDR (sign)")
+	value := flag.String("value", "", "授权额度, 例如 100.5, 按代币精度换算 (sign)")
+	ttl := flag.Duration("ttl", time.Hour, "permit 有效期 (sign)")
+	to := flag.String("to", "", "收款地址, 为空时转给当前账户 (Router 的 operator) (redeem)")
+	amount := flag.String("amount", "", "转账金额, 例如 1.5, 为空时使用授权额度 (redeem)")
+	flag.Parse()
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+
+	// 代币地址，默认使用 USDT_CONTRACT_ADDR
+	tokenHex := os.Getenv("PERMIT_TOKEN_ADDR")
+	if tokenHex == "" {
+		tokenHex = os.Getenv("USDT_CONTRACT_ADDR")
+	}
+	if !common.IsHexAddress(tokenHex) {
+		log.Fatal("err: 缺少 PERMIT_TOKEN_ADDR")
+	}
+	permitToken, err := permit.NewToken(common.HexToAddress(tokenHex), chain.GetChainID(ctx, client), client)
+	if err != nil {
+		log.Fatal("err: 连接代币合约失败 ", err)
+	}
+	meta, err := token.NewResolver(client).Get(ctx, permitToken.Address)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败 ", err)
+	}
+
+	switch *action {
+	case "info":
+		separator, err := permitToken.DomainSeparator(opts)
+		if err != nil {
+			log.Fatal("err: 代币不支持 permit ", err)
+		}
+		fmt.Printf("代币: %s\n", permitToken.Address.Hex())
+		fmt.Printf("   DOMAIN_SEPARATOR: %s\n", separator.Hex())
+		domain, err := permitToken.Domain(opts)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		fmt.Printf("   EIP-712 域: name=%q version=%q chainId=%s\n", domain.Name, domain.Version, (*big.Int)(domain.ChainId))
+		if os.Getenv("PRIVATE_KEY") != "" {
+			user := model.NewUserFromEnv(client)
+			nonce, err := permitToken.Nonce(opts, user.Address)
+			if err != nil {
+				log.Fatal("err: 读取 nonce 失败 ", err)
+			}
+			fmt.Printf("   %s 的 nonce: %s\n", user.Address.Hex(), nonce)
+		}
+
+	case "deploy-router":
+		// 部署 PermitRouter，当前账户 (服务端热钱包) 是唯一可以兑现 permit 的 operator
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		router, tx, err := permit.DeployRouter(auth, client)
+		if err != nil {
+			log.Fatal("err: 部署 PermitRouter 失败 ", err)
+		}
+		fmt.Printf("部署交易: %s\n", tx.Hash().Hex())
+		if _, err := bind.WaitDeployed(ctx, client, tx); err != nil {
+			log.Fatal("err: 等待部署失败 ", err)
+		}
+		fmt.Printf("PermitRouter: %s, operator: %s\n", router.Hex(), user.Address.Hex())
+		fmt.Println("请设置 PERMIT_ROUTER_ADDR 后再签名和兑现 permit")
+
+	case "sign":
+		// 代币持有人签名，不需要 gas
+		if *spender == "" {
+			*spender = os.Getenv("PERMIT_ROUTER_ADDR")
+		}
+		if !common.IsHexAddress(*spender) {
+			log.Fatal("err: 无效的 spender ", *spender)
+		}
+		val, err := meta.Parse(*value)
+		if err != nil || val.Sign() <= 0 {
+			log.Fatal("err: 无效的授权额度 ", *value, " ", err)
+		}
+		user := model.NewUserFromEnv(client)
+		p, err := permitToken.NewPermit(opts, user.Address, common.HexToAddress(*spender), val.Int(), time.Now().Add(*ttl))
+		if err != nil {
+			log.Fatal("err: 读取 nonce 失败 ", err)
+		}
+		if err := permitToken.Sign(opts, user, p); err != nil {
+			log.Fatal("err: 签名失败 ", err)
+		}
+		if err := p.Save(*file); err != nil {
+			log.Fatal("err: 保存 permit 失败 ", err)
+		}
+		fmt.Printf("permit 已签名: %s\n", *file)
+		fmt.Printf("   owner: %s -> spender: %s, 额度 %s %s, nonce %s\n", p.Owner.Hex(), p.Spender.Hex(), meta.Amount(p.Value.ToInt()), meta.Symbol, p.Nonce.ToInt())
+
+	case "verify":
+		p := loadPermit(*file)
+		if err := permitToken.Verify(opts, p); err != nil {
+			log.Fatal("err: permit 无效 ", err)
+		}
+		fmt.Println("permit 验证通过")
+
+	case "redeem":
+		// 服务端 (Router 的 operator) 通过 Router 一次提交 permit 和 transferFrom，支付 gas
+		p := loadPermit(*file)
+		router := routerAddress()
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		recipient := user.Address
+		if *to != "" {
+			if !common.IsHexAddress(*to) {
+				log.Fatal("err: 无效的收款地址 ", *to)
+			}
+			recipient = common.HexToAddress(*to)
+		}
+		amt := meta.Amount(p.Value.ToInt())
+		if *amount != "" {
+			if amt, err = meta.Parse(*amount); err != nil {
+				log.Fatal("err: 无效的金额 ", *amount, " ", err)
+			}
+		}
+		fmt.Printf("转账 %s %s 给 %s\n", amt, meta.Symbol, recipient.Hex())
+
+		tx, err := permitToken.Redeem(auth, router, p, recipient, amt.Int())
+		if err != nil {
+			log.Fatal("err: 兑现 permit 失败 ", err)
+		}
+		fmt.Printf("兑现交易: %s\n", tx.Hash().Hex())
+
+		// 核对收据中的 Transfer 日志
+		check, err := token.VerifyTransfer(ctx, client, tx, permitToken.Address, p.Owner, recipient, amt.Int())
+		if check == nil {
+			log.Fatal("err: 等待上链失败 ", err)
+		}
+		if err != nil {
+			log.Fatal("err: 转账核对失败 ", err)
+		}
+		fmt.Printf("转账完成, 区块: %d, 到账 %s %s\n", check.Receipt.BlockNumber, meta.Amount(check.Received), meta.Symbol)
+
+	default:
+		log.Fatal("err: 未知操作 ", *action)
+	}
+}
+
+// routerAddress 读取 PERMIT_ROUTER_ADDR
+func routerAddress() common.Address {
+	hex := os.Getenv("PERMIT_ROUTER_ADDR")
+	if !common.IsHexAddress(hex) {
+		log.Fatal("err: 缺少 PERMIT_ROUTER_ADDR, 请先执行 -action deploy-router")
+	}
+	return common.HexToAddress(hex)
+}
+
+func loadPermit(path string) *permit.Permit {
+	p, err := permit.LoadPermit(path)
+	if err != nil {
+		log.Fatal("err: 读取 permit 失败 ", err)
+	}
+	return p
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"io"
+	"learn-web3-go/cmd/11_api_server/auth"
+	"learn-web3-go/pkg/apikey"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "list", "操作: issue | list | revoke | call")
+	name := flag.String("name", "", "API key 名字, 例如调用方服务名 (issue)")
+	role := flag.String("role", "viewer", "角色: viewer | operator | approver | admin (issue)")
+	scopes := flag.String("scopes", "read", "接口范围, 逗号分隔: read,transfer,permit,admin 或 * (issue)")
+	ttl := flag.Duration("ttl", 0, "有效期, 0 表示永不过期 (issue)")
+	id := flag.String("id", "", "API key ID (revoke)")
+	method := flag.String("method", "GET", "请求方法 (call)")
+	url := flag.String("url", "http://localhost:8888/auth/me", "请求地址 (call)")
+	body := flag.String("body", "", "请求体 JSON (call)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	switch *action {
+	case "issue":
+		if *name == "" {
+			log.Fatal("err: 缺少 -name")
+		}
+		// 与网关签发接口相同的检查，拼错的角色会让网关拒绝这个 key 的所有请求
+		parsedRole, err := auth.ParseRole(*role)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		parsedScopes, err := auth.ParseScopes(strings.Split(*scopes, ","))
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		store := loadStore()
+		key, secret, err := store.Issue(*name, string(parsedRole), parsedScopes, *ttl)
+		if err != nil {
+			log.Fatal("err: 签发失败 ", err)
+		}
+		if err := store.Save(); err != nil {
+			log.Fatal("err: 保存失败 ", err)
+		}
+		fmt.Println("API key 已签发, secret 只显示这一次, 请交给调用方妥善保存:")
+		fmt.Printf("   API_KEY_ID=%s\n", key.ID)
+		fmt.Printf("   API_KEY_SECRET=%s\n", secret)
+		fmt.Printf("   角色: %s, 范围: %s\n", key.Role, strings.Join(key.Scopes, ","))
+		if key.ExpiresAt != nil {
+			fmt.Printf("   过期时间: %s\n", key.ExpiresAt.Local().Format(time.DateTime))
+		}
+
+	case "list":
+		store := loadStore()
+		now := time.Now()
+		for _, key := range store.List() {
+			status := "有效"
+			if err := key.Active(now); err != nil {
+				status = err.Error()
+			}
+			fmt.Printf("%s  %-16s %-9s %-20s %s\n", key.ID, key.Name, key.Role, strings.Join(key.Scopes, ","), status)
+		}
+
+	case "revoke":
+		store := loadStore()
+		if err := store.Revoke(*id); err != nil {
+			log.Fatal("err: ", err)
+		}
+		if err := store.Save(); err != nil {
+			log.Fatal("err: 保存失败 ", err)
+		}
+		fmt.Printf("%s 已吊销\n", *id)
+
+	case "call":
+		// 模拟后端服务用 API key 调用网关
+		keyID, secret := os.Getenv("API_KEY_ID"), os.Getenv("API_KEY_SECRET")
+		if keyID == "" || secret == "" {
+			log.Fatal("err: 缺少 API_KEY_ID 或 API_KEY_SECRET")
+		}
+		req, err := http.NewRequest(*method, *url, strings.NewReader(*body))
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		if *body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if err := apikey.SignRequest(req, keyID, secret); err != nil {
+			log.Fatal("err: 签名失败 ", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatal("err: 请求失败 ", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		fmt.Printf("%s\n%s\n", resp.Status, data)
+
+	default:
+		log.Fatal("err: 未知操作 ", *action)
+	}
+}
+
+func loadStore() *apikey.Store {
+	store, err := apikey.LoadFromEnv()
+	if err != nil {
+		log.Fatal("err: 读取 API key 文件失败 ", err)
+	}
+	return store
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/token"
+	"log"
+	"math/big"
+	"os"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "show", "操作: show | approve | revoke | transferFrom")
+	spender := flag.String("spender", "", "被授权的地址或地址簿中的名字")
+	owner := flag.String("owner", "", "授权人地址, 为空时使用 PRIVATE_KEY 的地址 (show / transferFrom)")
+	to := flag.String("to", "", "收款地址 (transferFrom)")
+	amount := flag.String("amount", "", "金额, 例如 1.5, 按代币精度换算 (approve / transferFrom)")
+	flag.Parse()
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(ctx, client).Uint64()
+	user := model.NewUserFromEnv(client)
+
+	tokenAddr := common.HexToAddress(os.Getenv("USDT_CONTRACT_ADDR"))
+	usdt, err := erc20.NewERC20(tokenAddr, client)
+	if err != nil {
+		log.Fatal(err)
+	}
+	meta, err := token.NewResolver(client).Get(ctx, tokenAddr)
+	if err != nil {
+		log.Fatal("err: 查询代币信息失败 ", err)
+	}
+
+	resolve := func(s string) common.Address {
+		addr, err := book.Resolve(chainID, s)
+		if err != nil {
+			log.Fatal("err: 无效的地址 ", s)
+		}
+		return addr
+	}
+	parseAmount := func() *big.Int {
+		v, err := meta.Parse(*amount)
+		if err != nil || v.Sign() <= 0 {
+			log.Fatal("err: 无效的金额 ", *amount, " ", err)
+		}
+		return v.Int()
+	}
+	ownerAddr := user.Address
+	if *owner != "" {
+		ownerAddr = resolve(*owner)
+	}
+
+	switch *action {
+	case "show":
+		spenderAddr := resolve(*spender)
+		allowance, err := usdt.Allowance(opts, ownerAddr, spenderAddr)
+		if err != nil {
+			log.Fatal("err: 查询额度失败 ", err)
+		}
+		fmt.Printf("%s -> %s 的额度: %s %s\n", book.Label(chainID, ownerAddr), book.Label(chainID, spenderAddr), meta.Amount(allowance), meta.Symbol)
+
+	case "approve":
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		txs, err := token.EnsureAllowance(auth, client, tokenAddr, resolve(*spender), parseAmount())
+		if err != nil {
+			log.Fatal("err: 授权失败 ", err)
+		}
+		if len(txs) == 0 {
+			fmt.Println("额度已经足够，不需要授权")
+			return
+		}
+		for _, tx := range txs {
+			fmt.Printf("授权交易: %s\n", tx.Hash().Hex())
+		}
+		waitMined(ctx, client, txs[len(txs)-1])
+
+	case "revoke":
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		tx, err := token.Revoke(auth, usdt, resolve(*spender))
+		if err != nil {
+			log.Fatal("err: 取消授权失败 ", err)
+		}
+		fmt.Printf("取消授权交易: %s\n", tx.Hash().Hex())
+		waitMined(ctx, client, tx)
+
+	case "transferFrom":
+		// 当前账户作为 spender，从 owner 转出
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		tx, err := token.TransferFrom(auth, usdt, ownerAddr, resolve(*to), parseAmount())
+		if err != nil {
+			log.Fatal("err: transferFrom 失败 ", err)
+		}
+		fmt.Printf("transferFrom 交易: %s\n", tx.Hash().Hex())
+		waitMined(ctx, client, tx)
+
+	default:
+		log.Fatal("err: 未知操作 ", *action)
+	}
+}
+
+func waitMined(ctx context.Context, client bind.DeployBackend, tx *types.Transaction) {
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		log.Fatal("err: 等待上链失败 ", err)
+	}
+	fmt.Printf("已上链, 状态: %d, 区块: %d\n", receipt.Status, receipt.BlockNumber)
+}
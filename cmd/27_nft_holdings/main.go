@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"learn-web3-go/contracts/erc1155"
+	"learn-web3-go/contracts/erc721"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/nft"
+	"log"
+	"math/big"
+	"os"
+)
+
+func main() {
+	// 命令行参数
+	action := flag.String("action", "list", "操作: list | history | transfer | approveAll")
+	owner := flag.String("owner", "", "持有人地址或地址簿中的名字, 为空时使用 PRIVATE_KEY 的地址 (list / history)")
+	source := flag.String("source", "auto", "ERC-721 持有列表的来源: auto | events | enumerable (list)")
+	fromBlock := flag.Uint64("from", 0, "回放事件的起始区块, 应该不晚于合约部署的区块 (list / history)")
+	chunk := flag.Uint64("chunk", 10000, "初始每次查询日志的区块数, 会根据结果自动调整 (list / history)")
+	to := flag.String("to", "", "收款地址或地址簿中的名字 (transfer)")
+	tokenID := flag.String("id", "", "tokenId (transfer)")
+	amount := flag.String("amount", "1", "转账数量, 只用于 ERC-1155 (transfer)")
+	operator := flag.String("operator", "", "授权管理全部 NFT 的地址 (approveAll)")
+	revoke := flag.Bool("revoke", false, "取消授权 (approveAll)")
+	flag.Parse()
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(ctx, client).Uint64()
+
+	if !common.IsHexAddress(os.Getenv("NFT_CONTRACT_ADDR")) {
+		log.Fatal("err: 缺少 NFT_CONTRACT_ADDR")
+	}
+	contract := common.HexToAddress(os.Getenv("NFT_CONTRACT_ADDR"))
+	token, err := erc721.NewERC721(contract, client)
+	if err != nil {
+		log.Fatal(err)
+	}
+	multi, err := erc1155.NewERC1155(contract, client)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ERC-165 区分 ERC-1155 和 ERC-721
+	is1155 := nft.SupportsInterface(opts, multi, nft.InterfaceERC1155)
+	if !is1155 && !nft.SupportsInterface(opts, token, nft.InterfaceERC721) {
+		log.Println("warn: 合约没有通过 ERC-165 声明支持 ERC-721 或 ERC-1155, 按 ERC-721 处理")
+	}
+
+	resolve := func(s string) common.Address {
+		addr, err := book.Resolve(chainID, s)
+		if err != nil {
+			log.Fatal("err: 无效的地址 ", s)
+		}
+		return addr
+	}
+	ownerAddr := func() common.Address {
+		if *owner != "" {
+			return resolve(*owner)
+		}
+		return model.NewUserFromEnv(client).Address
+	}
+	latest := func() uint64 {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			log.Fatal("err: 获取最新区块失败 ", err)
+		}
+		return head
+	}
+
+	switch *action {
+	case "list":
+		holder := ownerAddr()
+		if is1155 {
+			fmt.Printf("合约: ERC-1155 (%s)\n", contract.Hex())
+		} else {
+			name, _ := token.Name(opts)
+			symbol, _ := token.Symbol(opts)
+			fmt.Printf("合约: %s %s (%s)\n", name, symbol, contract.Hex())
+		}
+		fmt.Printf("持有人: %s\n", book.Label(chainID, holder))
+
+		var holdings []nft.Holding
+		switch {
+		case is1155:
+			// ERC-1155 没有枚举接口，先回放事件找到 id，再用 balanceOfBatch 读取实际数量
+			head := latest()
+			fmt.Printf("来源: TransferSingle/TransferBatch 事件, 区块 %d -> %d\n", *fromBlock, head)
+			if holdings, err = nft.HoldingsFromEvents(ctx, client, contract, holder, *fromBlock, head, *chunk); err == nil {
+				holdings, err = nft.HoldingsOf(opts, multi, holder, holdings)
+			}
+		case *source == "enumerable" || *source == "auto" && nft.SupportsInterface(opts, token, nft.InterfaceERC721Enumerable):
+			// 实现了 Enumerable 时直接读取链上状态，否则回放 Transfer 事件
+			fmt.Println("来源: ERC721Enumerable")
+			holdings, err = nft.HoldingsEnumerable(opts, token, holder)
+		case *source == "events" || *source == "auto":
+			head := latest()
+			fmt.Printf("来源: Transfer 事件, 区块 %d -> %d\n", *fromBlock, head)
+			holdings, err = nft.HoldingsFromEvents(ctx, client, contract, holder, *fromBlock, head, *chunk)
+		default:
+			log.Fatal("err: 未知的来源 ", *source)
+		}
+		if err != nil {
+			log.Fatal("err: 查询持有列表失败 ", err)
+		}
+
+		withURI := is1155 || nft.SupportsInterface(opts, token, nft.InterfaceERC721Metadata)
+		for _, h := range holdings {
+			line := fmt.Sprintf("   #%s", h.TokenID)
+			if is1155 {
+				line += fmt.Sprintf(" x %s", h.Balance)
+			}
+			if h.Block > 0 {
+				line += fmt.Sprintf(" (区块 %d 转入)", h.Block)
+				// 事件范围不完整时结果可能过期，用 ownerOf 复核
+				if !is1155 {
+					if current, err := token.OwnerOf(opts, h.TokenID); err == nil && current != holder {
+						line += fmt.Sprintf(" [已不属于该地址, 当前持有人 %s]", book.Label(chainID, current))
+					}
+				}
+			}
+			if withURI {
+				var uri string
+				if is1155 {
+					uri, err = nft.TokenURI(opts, multi, h.TokenID)
+				} else {
+					uri, err = token.TokenURI(opts, h.TokenID)
+				}
+				if err == nil {
+					line += " " + uri
+				}
+			}
+			fmt.Println(line)
+		}
+		if is1155 {
+			fmt.Printf("共 %d 种\n", len(holdings))
+		} else if balance, err := token.BalanceOf(opts, holder); err == nil {
+			fmt.Printf("共 %d 个, 链上 balanceOf: %s\n", len(holdings), balance)
+		}
+
+	case "history":
+		// 转入和转出的全部记录，ERC-1155 的批量转账按 id 拆开显示
+		holder := ownerAddr()
+		head := latest()
+		movements, err := nft.FetchMovements(ctx, client, contract, holder, *fromBlock, head, *chunk)
+		if err != nil {
+			log.Fatal("err: 查询转账记录失败 ", err)
+		}
+		fmt.Printf("%s 的转账记录, 区块 %d -> %d\n", book.Label(chainID, holder), *fromBlock, head)
+		for _, m := range movements {
+			direction := "转入"
+			if m.From == holder {
+				direction = "转出"
+			}
+			fmt.Println("------------------------------------------------------------")
+			fmt.Printf("%s %s #%s x %s\n", m.Kind, direction, m.ID, m.Value)
+			fmt.Printf("区块高度: %d, 交易哈希: %s\n", m.Block, m.TxHash.Hex())
+			fmt.Printf("From: %s\n", book.Label(chainID, m.From))
+			fmt.Printf("To:   %s\n", book.Label(chainID, m.To))
+			if m.Operator != (common.Address{}) && m.Operator != m.From {
+				fmt.Printf("操作人: %s\n", book.Label(chainID, m.Operator))
+			}
+		}
+		fmt.Printf("共 %d 条\n", len(movements))
+
+	case "transfer":
+		// safeTransferFrom 会检查接收方合约是否实现了 onERC721Received / onERC1155Received，避免 NFT 被锁在合约里
+		id, ok := new(big.Int).SetString(*tokenID, 10)
+		if !ok {
+			log.Fatal("err: 无效的 tokenId ", *tokenID)
+		}
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		var tx *types.Transaction
+		if is1155 {
+			value, ok := new(big.Int).SetString(*amount, 10)
+			if !ok || value.Sign() <= 0 {
+				log.Fatal("err: 无效的数量 ", *amount)
+			}
+			balance, err := multi.BalanceOf(opts, user.Address, id)
+			if err != nil {
+				log.Fatal("err: 查询余额失败 ", err)
+			}
+			if balance.Cmp(value) < 0 {
+				log.Fatalf("err: #%s 余额不足, 持有 %s", id, balance)
+			}
+			tx, err = multi.SafeTransferFrom(auth, user.Address, resolve(*to), id, value, nil)
+			if err != nil {
+				log.Fatal("err: 转账失败 ", err)
+			}
+		} else {
+			current, err := token.OwnerOf(opts, id)
+			if err != nil {
+				log.Fatal("err: 查询持有人失败 ", err)
+			}
+			if current != user.Address {
+				log.Fatalf("err: #%s 属于 %s, 不是当前账户", id, book.Label(chainID, current))
+			}
+			tx, err = token.SafeTransferFrom(auth, user.Address, resolve(*to), id)
+			if err != nil {
+				log.Fatal("err: 转账失败 ", err)
+			}
+		}
+		fmt.Printf("转账交易: %s\n", tx.Hash().Hex())
+		waitMined(ctx, client, tx)
+
+	case "approveAll":
+		// ERC-721 和 ERC-1155 的 setApprovalForAll 签名相同
+		user := model.NewUserFromEnv(client)
+		auth, err := chain.NewAuth(client, user)
+		if err != nil {
+			log.Fatal("生成凭证失败", err)
+		}
+		tx, err := token.SetApprovalForAll(auth, resolve(*operator), !*revoke)
+		if err != nil {
+			log.Fatal("err: 授权失败 ", err)
+		}
+		fmt.Printf("授权交易: %s\n", tx.Hash().Hex())
+		waitMined(ctx, client, tx)
+
+	default:
+		log.Fatal("err: 未知操作 ", *action)
+	}
+}
+
+func waitMined(ctx context.Context, client bind.DeployBackend, tx *types.Transaction) {
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		log.Fatal("err: 等待上链失败 ", err)
+	}
+	fmt.Printf("已上链, 状态: %d, 区块: %d\n", receipt.Status, receipt.BlockNumber)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/joho/godotenv"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/nft"
+	"log"
+	"os"
+)
+
+func main() {
+	godotenv.Load()
+
+	// 连接 WebSocket
+	client := chain.InitWSClient()
+	fmt.Println("socket 连接成功...")
+
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(context.Background(), client).Uint64()
+
+	// 只订阅 ERC-721 Transfer 和 ERC-1155 TransferSingle/TransferBatch 事件
+	// NFT_CONTRACT_ADDR 为空时监听链上所有 NFT 合约
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{{nft.TransferTopic, nft.TransferSingleTopic, nft.TransferBatchTopic}},
+	}
+	if addr := os.Getenv("NFT_CONTRACT_ADDR"); common.IsHexAddress(addr) {
+		query.Addresses = []common.Address{common.HexToAddress(addr)}
+		fmt.Printf("正在监听 %s 的 NFT 转账\n", addr)
+	} else {
+		fmt.Println("正在监听链上所有的 NFT 转账")
+	}
+
+	logs := make(chan types.Log)
+	sub, err := client.SubscribeFilterLogs(context.Background(), query, logs)
+	if err != nil {
+		log.Fatal("err: 订阅失败", err)
+	}
+
+	for {
+		select {
+		case err := <-sub.Err():
+			log.Fatal("err: 监听订阅失败", err)
+
+		case vLog := <-logs:
+			// ERC-20 的 Transfer topic0 与 ERC-721 相同，只有 3 个 topic，DecodeMovements 返回空
+			movements, err := nft.DecodeMovements(vLog)
+			if err != nil || len(movements) == 0 {
+				continue
+			}
+
+			first := movements[0]
+			kind := "转账"
+			switch {
+			case first.From == (common.Address{}):
+				kind = "铸造"
+			case first.To == (common.Address{}):
+				kind = "销毁"
+			}
+			fmt.Printf("\n 捕捉到一笔 %s %s\n", first.Kind, kind)
+			fmt.Printf("合约:    %s\n", vLog.Address.Hex())
+			fmt.Printf("From:    %s\n", book.Label(chainID, first.From))
+			fmt.Printf("To:      %s\n", book.Label(chainID, first.To))
+			if first.Operator != (common.Address{}) && first.Operator != first.From {
+				fmt.Printf("操作人:  %s\n", book.Label(chainID, first.Operator))
+			}
+			// ERC-1155 的批量转账包含多个 id
+			for _, m := range movements {
+				if m.Kind == nft.KindERC721 {
+					fmt.Printf("TokenId: %s\n", m.ID)
+				} else {
+					fmt.Printf("TokenId: %s x %s\n", m.ID, m.Value)
+				}
+			}
+			fmt.Printf("Tx:      %s\n", vLog.TxHash.Hex())
+		}
+	}
+}
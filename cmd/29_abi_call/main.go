@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/contract"
+	"learn-web3-go/pkg/proxy"
+	"learn-web3-go/utils"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// 用法:
+//
+//	go run ./cmd/29_abi_call -abi abi/erc20.abi -address USDT -method balanceOf alice
+//	go run ./cmd/29_abi_call -abi abi/erc20.abi -address USDT -method transfer bob 1000000
+//	go run ./cmd/29_abi_call -abi abi/multicall.abi -address 0xcA11bde05977b3631167028862bE2a173976CA11 -method aggregate3 '[["0x...",true,"0x18160ddd"]]'
+//	go run ./cmd/29_abi_call -abi abi/erc20.abi -list
+//	go run ./cmd/29_abi_call -address USDC -method implementation   # 不指定 -abi 时按实现合约在 abi/ 中查找
+func main() {
+	// 命令行参数，方法的参数按顺序写在最后
+	abiSource := flag.String("abi", "", "ABI 文件路径 (abi/ 目录下的 .abi 或编译产物 JSON), 或者内联的 ABI JSON, 为空时按合约地址自动查找")
+	abiDir := flag.String("abi-dir", "abi", "自动查找 ABI 的目录, 文件名为实现合约 (或合约本身) 的地址或地址簿中的名字")
+	address := flag.String("address", "", "合约地址或地址簿中的名字")
+	method := flag.String("method", "", "方法名, 有重载时使用签名, 例如 safeTransferFrom(address,address,uint256)")
+	list := flag.Bool("list", false, "列出 ABI 中的全部方法")
+	value := flag.String("value", "", "附带的 ETH 数量, 例如 0.01 (payable 方法)")
+	block := flag.Int64("block", -1, "在指定区块调用, 默认为最新区块 (只读方法)")
+	dryRun := flag.Bool("dry-run", false, "写方法只通过 eth_call 模拟, 不发送交易")
+	flag.Parse()
+
+	// 只列出方法时不需要连接节点
+	if *list && *abiSource != "" {
+		parsed, err := contract.LoadABI(*abiSource)
+		if err != nil {
+			log.Fatal("err: 加载 ABI 失败 ", err)
+		}
+		listMethods(parsed)
+		return
+	}
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	book := addressbook.LoadFromEnv()
+	chainID := chain.GetChainID(ctx, client).Uint64()
+	resolve := func(s string) (common.Address, error) {
+		return book.Resolve(chainID, s)
+	}
+
+	contractAddr, err := resolve(*address)
+	if err != nil {
+		log.Fatal("err: 无效的合约地址 ", *address)
+	}
+
+	// 指定区块时按该区块的状态调用，代理也按该区块读取，升级前后的实现合约可能不同
+	var blockNumber *big.Int
+	if *block >= 0 {
+		blockNumber = big.NewInt(*block)
+	}
+
+	// 可升级代理合约的方法定义在实现合约中，调用仍然发给代理地址
+	impl, hops, err := proxy.Resolve(ctx, client, contractAddr, blockNumber)
+	if errors.Is(err, proxy.ErrNotContract) && len(hops) == 0 {
+		log.Fatal("err: 地址上没有合约代码 ", contractAddr.Hex())
+	}
+	if err != nil {
+		log.Fatal("err: 检测代理合约失败 ", err)
+	}
+	for _, info := range hops {
+		fmt.Printf("%s 是 %s 代理, 实现合约: %s\n", book.Label(chainID, info.Proxy), info.Kind, book.Label(chainID, info.Implementation))
+		if info.Admin != (common.Address{}) {
+			fmt.Printf("  管理员: %s\n", book.Label(chainID, info.Admin))
+		}
+		if info.Beacon != (common.Address{}) {
+			fmt.Printf("  Beacon: %s\n", book.Label(chainID, info.Beacon))
+		}
+	}
+
+	var parsed abi.ABI
+	if *abiSource != "" {
+		parsed, err = contract.LoadABI(*abiSource)
+	} else {
+		// 优先使用实现合约的 ABI，找不到时再用代理合约自己的
+		var path string
+		parsed, path, err = contract.FindABI(*abiDir,
+			impl.Hex(), book.Name(chainID, impl),
+			contractAddr.Hex(), book.Name(chainID, contractAddr))
+		if err == nil {
+			fmt.Printf("使用 ABI: %s\n", path)
+		}
+	}
+	if err != nil {
+		log.Fatal("err: 加载 ABI 失败 ", err)
+	}
+	if *list {
+		listMethods(parsed)
+		return
+	}
+	c := contract.New(contractAddr, parsed, client)
+
+	m, err := c.Method(*method)
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	params, err := contract.ParseArgs(m.Inputs, flag.Args(), resolve)
+	if err != nil {
+		log.Fatalf("err: %s: %v", m.Sig, err)
+	}
+
+	// 只读方法和 dry-run 通过 eth_call 执行
+	if m.IsConstant() || *dryRun {
+		opts := &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
+		if !m.IsConstant() {
+			// 模拟写方法时以当前账户的身份调用，否则 msg.sender 为零地址
+			opts.From = model.NewUserFromEnv(client).Address
+		}
+		out, err := c.Call(opts, m, params...)
+		if err != nil {
+			log.Fatal("err: 调用失败 ", err)
+		}
+		printJSON(contract.FormatOutputs(m.Outputs, out))
+		return
+	}
+
+	// 写方法: 与其它脚本一样使用 PRIVATE_KEY 签名，手续费由 chain.NewAuth 设置
+	user := model.NewUserFromEnv(client)
+	auth, err := chain.NewAuth(client, user)
+	if err != nil {
+		log.Fatal("生成凭证失败", err)
+	}
+	if *value != "" {
+		wei, err := utils.EtherToWei(*value)
+		if err != nil {
+			log.Fatal("err: 无效的 ETH 数量 ", err)
+		}
+		auth.Value = wei
+	}
+	tx, err := c.Transact(auth, m, params...)
+	if err != nil {
+		log.Fatal("err: 发送交易失败 ", err)
+	}
+	fmt.Printf("交易已发送: %s\n", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		log.Fatal("err: 等待上链失败 ", err)
+	}
+	fmt.Printf("已上链, 状态: %d, 区块: %d, Gas: %d\n", receipt.Status, receipt.BlockNumber, receipt.GasUsed)
+	if events := c.DecodeLogs(receipt); len(events) > 0 {
+		printJSON(events)
+	}
+	if receipt.Status == 0 {
+		os.Exit(1)
+	}
+}
+
+func listMethods(parsed abi.ABI) {
+	c := contract.New(common.Address{}, parsed, nil)
+	for _, m := range c.Methods() {
+		fmt.Printf("%-10s %s -> (%s)\n", m.StateMutability, m.Sig, outputTypes(m.Outputs))
+	}
+}
+
+func outputTypes(args []abi.Argument) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+	}
+	return strings.Join(types, ",")
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/pkg/addressbook"
+	"learn-web3-go/pkg/chain"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/contract"
+	"learn-web3-go/pkg/deploy"
+	"learn-web3-go/utils"
+	"log"
+	"strconv"
+)
+
+// 用法:
+//
+//	go run ./cmd/30_deploy -artifact out/MockToken.sol/MockToken.json "Mock USD" mUSD 6
+//	go run ./cmd/30_deploy -abi build/Distributor.abi -bin build/Distributor.bin -mode create2 -salt distributor-v1 USDT
+//	go run ./cmd/30_deploy -artifact artifacts/Forwarder.json -mode create2 -salt 0x01 -predict
+func main() {
+	// 命令行参数，构造函数的参数按顺序写在最后
+	artifactPath := flag.String("artifact", "", "Hardhat / Foundry 编译产物 JSON")
+	abiSource := flag.String("abi", "", "ABI 文件或内联 ABI JSON, 与 -bin 一起使用")
+	binPath := flag.String("bin", "", "solc --bin 输出的字节码文件")
+	mode := flag.String("mode", "create", "部署方式: create | create2")
+	saltFlag := flag.String("salt", "", "CREATE2 的 salt, 0x 开头的十六进制或任意字符串 (字符串会做 keccak256)")
+	predict := flag.Bool("predict", false, "只计算部署地址, 不发送交易")
+	value := flag.String("value", "", "构造函数附带的 ETH 数量, 例如 0.01 (payable 构造函数)")
+	name := flag.String("name", "", "部署成功后以这个名字保存到地址簿")
+	flag.Parse()
+
+	// 读取字节码和 ABI
+	var (
+		artifact *deploy.Artifact
+		err      error
+	)
+	switch {
+	case *artifactPath != "":
+		artifact, err = deploy.LoadArtifact(*artifactPath)
+	case *abiSource != "" && *binPath != "":
+		artifact, err = deploy.LoadABIBin(*abiSource, *binPath)
+	default:
+		log.Fatal("err: 需要 -artifact 或者 -abi 和 -bin")
+	}
+	if err != nil {
+		log.Fatal("err: 读取合约失败 ", err)
+	}
+
+	// 初始化环境
+	client := chain.InitClient()
+	ctx := context.Background()
+	book := addressbook.LoadFromEnv()
+	if *name != "" {
+		// 部署后要写回地址簿，格式有误时在部署前退出，不能用空地址簿覆盖原文件
+		if book, err = addressbook.Load(addressbook.PathFromEnv()); err != nil {
+			log.Fatal("err: 加载地址簿失败 ", err)
+		}
+	}
+	chainID := chain.GetChainID(ctx, client).Uint64()
+	user := model.NewUserFromEnv(client)
+
+	// 构造函数参数，地址可以写地址簿中的名字
+	args, err := contract.ParseArgs(artifact.ABI.Constructor.Inputs, flag.Args(), func(s string) (common.Address, error) {
+		return book.Resolve(chainID, s)
+	})
+	if err != nil {
+		log.Fatal("err: 构造函数参数: ", err)
+	}
+	initCode, err := artifact.InitCode(args...)
+	if err != nil {
+		log.Fatal("err: ", err)
+	}
+	fmt.Printf("initCode: %d 字节, 哈希 %s\n", len(initCode), crypto.Keccak256Hash(initCode).Hex())
+
+	auth, err := chain.NewAuth(client, user)
+	if err != nil {
+		log.Fatal("生成凭证失败", err)
+	}
+	if *value != "" {
+		if !artifact.Payable() {
+			log.Fatal("err: 构造函数不是 payable, 不能附带 ETH")
+		}
+		if auth.Value, err = utils.EtherToWei(*value); err != nil {
+			log.Fatal("err: 无效的 ETH 数量 ", err)
+		}
+	}
+
+	var d *deploy.Deployment
+	switch *mode {
+	case "create":
+		// 地址由部署账户和 nonce 决定，换一个 nonce 就是另一个地址
+		addr, nonce, err := deploy.Predict(ctx, client, auth)
+		if err != nil {
+			log.Fatal("err: 获取 nonce 失败 ", err)
+		}
+		fmt.Printf("CREATE: 部署账户 %s, nonce %d\n", user.Address.Hex(), nonce)
+		fmt.Printf("预测地址: %s\n", addr.Hex())
+		if *predict {
+			return
+		}
+		d, err = deploy.Create(ctx, auth, client, initCode)
+		if err != nil {
+			log.Fatal("err: 部署失败 ", err)
+		}
+
+	case "create2":
+		// 地址由代理地址、salt 和 initCode 决定，与部署账户和 nonce 无关，不同链上地址相同
+		if *saltFlag == "" {
+			log.Fatal("err: CREATE2 需要 -salt")
+		}
+		salt, err := deploy.ParseSalt(*saltFlag)
+		if err != nil {
+			log.Fatal("err: ", err)
+		}
+		addr := deploy.Create2Address(deploy.ProxyAddress, salt, initCode)
+		fmt.Printf("CREATE2: 代理 %s, salt %s\n", deploy.ProxyAddress.Hex(), common.Hash(salt).Hex())
+		fmt.Printf("预测地址: %s\n", addr.Hex())
+		if *predict {
+			return
+		}
+		d, err = deploy.Create2(ctx, auth, client, salt, initCode)
+		if errors.Is(err, deploy.ErrAlreadyDeployed) {
+			fmt.Println("该地址已经部署过, 跳过")
+			saveToBook(book, *name, chainID, addr)
+			return
+		}
+		if err != nil {
+			log.Fatal("err: 部署失败 ", err)
+		}
+
+	default:
+		log.Fatal("err: 未知的部署方式 ", *mode)
+	}
+
+	fmt.Printf("部署交易: %s\n", d.Tx.Hash().Hex())
+	receipt, err := d.Wait(ctx, client)
+	if err != nil {
+		log.Fatal("err: 部署失败 ", err)
+	}
+	fmt.Printf("已上链, 区块: %d, Gas: %d\n", receipt.BlockNumber, receipt.GasUsed)
+	fmt.Printf("合约地址: %s (已确认链上有代码)\n", d.Address.Hex())
+	saveToBook(book, *name, chainID, d.Address)
+}
+
+// saveToBook 把部署的合约保存到地址簿，只对当前链有效
+func saveToBook(book *addressbook.Book, name string, chainID uint64, addr common.Address) {
+	if name == "" {
+		return
+	}
+	err := book.Add(addressbook.Entry{
+		Name:     name,
+		Address:  addr,
+		ChainIDs: []uint64{chainID},
+		Notes:    "部署于链 " + strconv.FormatUint(chainID, 10),
+	})
+	if err == nil {
+		err = book.Save()
+	}
+	if err != nil {
+		log.Fatal("err: 保存到地址簿失败 ", err)
+	}
+	fmt.Printf("已保存到地址簿: %s\n", name)
+}
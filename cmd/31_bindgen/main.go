@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 从 abi/ 目录重新生成 contracts/ 下的全部绑定，在仓库根目录运行:
+//
+//	go run ./cmd/31_bindgen          # 重新生成
+//	go run ./cmd/31_bindgen -check   # 只检查, 绑定与 ABI 不一致时退出码为 1, 适合放在 CI 中
+//
+// 新增合约时在 abi/bindings.json 中加一条记录，不要手动运行 abigen
+
+// Config abi/bindings.json 的格式，路径相对于仓库根目录
+type Config struct {
+	Bindings []Binding `json:"bindings"`
+}
+
+// Binding 一个 ABI 对应一个绑定文件
+type Binding struct {
+	ABI     string `json:"abi"`
+	Type    string `json:"type"`    // 合约的 Go 类型名，例如 ERC20
+	Package string `json:"package"` // Go 包名
+	Out     string `json:"out"`
+	// Bin 可选的创建字节码文件 (十六进制)，配置后会生成 Deploy 函数
+	Bin string `json:"bin,omitempty"`
+	// Structs 给没有 internalType 的 tuple 命名，key 为 tuple 的规范类型，例如 "(address,bytes)": "Call"
+	// 没有命名时 abigen 会生成 Struct0、Struct1 这类名字
+	Structs map[string]string `json:"structs,omitempty"`
+	// Aliases 重命名方法、事件或错误，例如 Go 中有冲突的名字，直接传给 abigen
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// anonymousStruct abigen 给无法命名的 tuple 生成的类型
+var anonymousStruct = regexp.MustCompile(`(?m)^type (Struct\d+) struct`)
+
+func main() {
+	configPath := flag.String("config", "abi/bindings.json", "绑定配置文件")
+	check := flag.Bool("check", false, "只检查已提交的绑定是否与 ABI 一致, 不写文件")
+	flag.Parse()
+
+	data, err := os.ReadFile(*configPath)
+	if err != nil {
+		log.Fatal("err: 读取配置失败 ", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Fatal("err: 解析配置失败 ", err)
+	}
+
+	var stale []string
+	for _, b := range cfg.Bindings {
+		code, err := generate(b)
+		if err != nil {
+			log.Fatalf("err: %s: %v", b.ABI, err)
+		}
+		current, err := os.ReadFile(b.Out)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Fatal("err: ", err)
+		}
+		if bytes.Equal(current, code) {
+			fmt.Printf("未变化  %s\n", b.Out)
+			continue
+		}
+
+		if *check {
+			if current == nil {
+				fmt.Printf("缺失    %s\n", b.Out)
+			} else {
+				fmt.Printf("已过期  %s (与 %s 不一致)\n", b.Out, b.ABI)
+			}
+			stale = append(stale, b.Out)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(b.Out), 0755); err != nil {
+			log.Fatal("err: ", err)
+		}
+		if err := os.WriteFile(b.Out, code, 0644); err != nil {
+			log.Fatal("err: 写入失败 ", err)
+		}
+		fmt.Printf("已生成  %s\n", b.Out)
+	}
+
+	// abi/ 下没有配置的 ABI 不会生成绑定，提示一下
+	configured := make(map[string]bool)
+	for _, b := range cfg.Bindings {
+		configured[filepath.Clean(b.ABI)] = true
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(*configPath), "*.abi"))
+	sort.Strings(files)
+	for _, f := range files {
+		if !configured[filepath.Clean(f)] {
+			fmt.Printf("warn: %s 没有在 %s 中配置\n", f, *configPath)
+		}
+	}
+
+	if len(stale) > 0 {
+		fmt.Printf("\n%d 个绑定与 ABI 不一致, 请运行 go run ./cmd/31_bindgen 重新生成\n", len(stale))
+		os.Exit(1)
+	}
+}
+
+// generate 用 abigen 库生成绑定代码，结果只由 ABI 和配置决定
+func generate(b Binding) ([]byte, error) {
+	if b.ABI == "" || b.Type == "" || b.Package == "" || b.Out == "" {
+		return nil, errors.New("abi、type、package、out 都不能为空")
+	}
+	data, err := os.ReadFile(b.ABI)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Structs) > 0 {
+		if data, err = nameStructs(data, b.Structs); err != nil {
+			return nil, err
+		}
+	}
+	var bytecode string
+	if b.Bin != "" {
+		bin, err := os.ReadFile(b.Bin)
+		if err != nil {
+			return nil, err
+		}
+		bytecode = strings.TrimPrefix(strings.TrimSpace(string(bin)), "0x")
+	}
+	code, err := abigen.Bind([]string{b.Type}, []string{string(data)}, []string{bytecode}, nil, b.Package, nil, b.Aliases)
+	if err != nil {
+		return nil, err
+	}
+	if names := anonymousStruct.FindAllStringSubmatch(code, -1); len(names) > 0 {
+		var anonymous []string
+		for _, n := range names {
+			anonymous = append(anonymous, n[1])
+		}
+		return nil, fmt.Errorf("生成了没有名字的结构体 %s, 请在 ABI 中补充 internalType 或在配置的 structs 中命名", strings.Join(anonymous, ", "))
+	}
+	return []byte(code), nil
+}
+
+// nameStructs 给没有 internalType 的 tuple 参数补上 "struct 名字"，abigen 会以此命名结构体
+func nameStructs(data []byte, names map[string]string) ([]byte, error) {
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		for _, key := range []string{"inputs", "outputs"} {
+			params, _ := item[key].([]interface{})
+			for _, p := range params {
+				if param, ok := p.(map[string]interface{}); ok {
+					nameTuple(param, names)
+				}
+			}
+		}
+	}
+	return json.Marshal(items)
+}
+
+// nameTuple 递归处理 tuple 和 tuple 数组，返回参数的规范类型
+func nameTuple(param map[string]interface{}, names map[string]string) string {
+	typ, _ := param["type"].(string)
+	if !strings.HasPrefix(typ, "tuple") {
+		return typ
+	}
+	components, _ := param["components"].([]interface{})
+	elems := make([]string, 0, len(components))
+	for _, c := range components {
+		if component, ok := c.(map[string]interface{}); ok {
+			elems = append(elems, nameTuple(component, names))
+		}
+	}
+	canonical := "(" + strings.Join(elems, ",") + ")"
+	if internal, _ := param["internalType"].(string); internal == "" || internal == typ {
+		if name, ok := names[canonical]; ok {
+			// 保留数组后缀，例如 tuple[] -> struct Call[]
+			param["internalType"] = "struct " + name + strings.TrimPrefix(typ, "tuple")
+		}
+	}
+	return canonical + strings.TrimPrefix(typ, "tuple")
+}
@@ -0,0 +1,350 @@
+package addressbook
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultPath 默认的地址簿文件
+const DefaultPath = "./addressbook.json"
+
+// 常用的标签
+const (
+	TagExchange = "exchange"
+	TagTeam     = "team"
+	TagCold     = "cold-wallet"
+	TagHot      = "hot-wallet"
+)
+
+// csvHeader 导入导出 CSV 的表头
+var csvHeader = []string{"name", "address", "tags", "chains", "notes"}
+
+// Entry 地址簿中的一条记录
+type Entry struct {
+	Name     string         `json:"name"`
+	Address  common.Address `json:"address"`
+	Tags     []string       `json:"tags,omitempty"`
+	ChainIDs []uint64       `json:"chainIds,omitempty"` // 为空表示所有链通用
+	Notes    string         `json:"notes,omitempty"`
+}
+
+// Book 持久化的地址簿
+type Book struct {
+	mu      sync.RWMutex
+	path    string
+	entries []Entry
+}
+
+// Load 从文件读取地址簿，文件不存在时返回空地址簿
+func Load(path string) (*Book, error) {
+	b := &Book{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := b.ImportJSON(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("地址簿 %s 格式有误: %w", path, err)
+	}
+	return b, nil
+}
+
+// PathFromEnv 地址簿文件路径，ADDRESS_BOOK_FILE 为空时使用 DefaultPath
+func PathFromEnv() string {
+	if path := os.Getenv("ADDRESS_BOOK_FILE"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// LoadFromEnv 读取 ADDRESS_BOOK_FILE 指定的地址簿，读取失败时返回空地址簿，不影响主流程
+// 只用于展示和解析名字；需要写回文件时必须用 Load 并处理错误，否则文件损坏时 Save 会用空地址簿覆盖它
+func LoadFromEnv() *Book {
+	path := PathFromEnv()
+	b, err := Load(path)
+	if err != nil {
+		log.Println("warn: 加载地址簿失败,", err)
+		return &Book{path: path}
+	}
+	return b
+}
+
+// Save 写回文件
+func (b *Book) Save() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	data, err := json.MarshalIndent(b.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, data, 0644)
+}
+
+// Entries 返回所有记录的副本，按名字排序
+func (b *Book) Entries() []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entries := append([]Entry{}, b.entries...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Add 添加记录，名字在同一条链上唯一
+// 同名记录的链范围完全相同时覆盖 (更新)，范围不同但有重叠时返回错误，不重叠时各自保留，
+// 例如 USDT 可以分别记录主网 (1) 和 Sepolia (11155111) 的地址
+func (b *Book) Add(e Entry) error {
+	e.Name = strings.TrimSpace(e.Name)
+	if e.Name == "" {
+		return errors.New("名字不能为空")
+	}
+	// 名字不能是地址，否则解析时会产生歧义
+	if common.IsHexAddress(e.Name) {
+		return errors.New("名字不能是一个地址")
+	}
+	if e.Address == (common.Address{}) {
+		return errors.New("地址不能为空")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	replace := -1
+	for i, old := range b.entries {
+		if !strings.EqualFold(old.Name, e.Name) {
+			continue
+		}
+		if sameChains(old.ChainIDs, e.ChainIDs) {
+			replace = i
+		} else if old.overlaps(e) {
+			return fmt.Errorf("%s 已经存在, 链范围 %s 与 %s 重叠", e.Name, chainsLabel(old.ChainIDs), chainsLabel(e.ChainIDs))
+		}
+	}
+	if replace >= 0 {
+		b.entries[replace] = e
+		return nil
+	}
+	b.entries = append(b.entries, e)
+	return nil
+}
+
+// Remove 按名字删除适用于 chainID 的记录，chainID 为 0 时删除所有同名记录，返回删除的数量
+func (b *Book) Remove(name string, chainID uint64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	kept := b.entries[:0]
+	for _, e := range b.entries {
+		if strings.EqualFold(e.Name, name) && e.onChain(chainID) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	removed := len(b.entries) - len(kept)
+	b.entries = kept
+	return removed
+}
+
+// Lookup 按地址查找记录，chainID 为 0 时不限制链
+func (b *Book) Lookup(chainID uint64, addr common.Address) (Entry, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, e := range b.entries {
+		if e.Address == addr && e.onChain(chainID) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Resolve 把名字或者十六进制地址解析成地址
+func (b *Book) Resolve(chainID uint64, nameOrAddress string) (common.Address, error) {
+	nameOrAddress = strings.TrimSpace(nameOrAddress)
+	if common.IsHexAddress(nameOrAddress) {
+		return common.HexToAddress(nameOrAddress), nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, e := range b.entries {
+		if strings.EqualFold(e.Name, nameOrAddress) && e.onChain(chainID) {
+			return e.Address, nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("地址簿中找不到: %s", nameOrAddress)
+}
+
+// Name 返回地址对应的名字，没有记录时返回空字符串
+func (b *Book) Name(chainID uint64, addr common.Address) string {
+	e, ok := b.Lookup(chainID, addr)
+	if !ok {
+		return ""
+	}
+	return e.Name
+}
+
+// Label 返回用于展示的 "名字 [标签] (地址)"，没有记录时只返回地址
+func (b *Book) Label(chainID uint64, addr common.Address) string {
+	e, ok := b.Lookup(chainID, addr)
+	if !ok {
+		return addr.Hex()
+	}
+	if len(e.Tags) > 0 {
+		return fmt.Sprintf("%s [%s] (%s)", e.Name, strings.Join(e.Tags, ","), addr.Hex())
+	}
+	return fmt.Sprintf("%s (%s)", e.Name, addr.Hex())
+}
+
+// ImportJSON 从 JSON 数组导入记录，规则同 Add: 名字和链范围都相同的记录会被覆盖
+func (b *Book) ImportJSON(r io.Reader) error {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := b.Add(e); err != nil {
+			return fmt.Errorf("%s: %w", e.Name, err)
+		}
+	}
+	return nil
+}
+
+// ExportJSON 导出为 JSON 数组
+func (b *Book) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b.Entries())
+}
+
+// ImportCSV 从 CSV 导入，表头为 name,address,tags,chains,notes，多个标签和链 ID 用 | 分隔
+func (b *Book) ImportCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // 允许省略后面的可选字段
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], csvHeader[0]) {
+			continue // 跳过表头
+		}
+		if len(record) < 2 {
+			return fmt.Errorf("第 %d 行缺少字段", i+1)
+		}
+		if !common.IsHexAddress(record[1]) {
+			return fmt.Errorf("第 %d 行地址无效: %s", i+1, record[1])
+		}
+		e := Entry{Name: record[0], Address: common.HexToAddress(record[1])}
+		if len(record) > 2 {
+			e.Tags = splitList(record[2])
+		}
+		if len(record) > 3 {
+			for _, s := range splitList(record[3]) {
+				id, err := strconv.ParseUint(s, 10, 64)
+				if err != nil {
+					return fmt.Errorf("第 %d 行链 ID 无效: %s", i+1, s)
+				}
+				e.ChainIDs = append(e.ChainIDs, id)
+			}
+		}
+		if len(record) > 4 {
+			e.Notes = record[4]
+		}
+		if err := b.Add(e); err != nil {
+			return fmt.Errorf("第 %d 行: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ExportCSV 导出为 CSV
+func (b *Book) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range b.Entries() {
+		chains := make([]string, len(e.ChainIDs))
+		for i, id := range e.ChainIDs {
+			chains[i] = strconv.FormatUint(id, 10)
+		}
+		record := []string{e.Name, e.Address.Hex(), strings.Join(e.Tags, "|"), strings.Join(chains, "|"), e.Notes}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// onChain 记录是否适用于指定的链
+func (e Entry) onChain(chainID uint64) bool {
+	if chainID == 0 || len(e.ChainIDs) == 0 {
+		return true
+	}
+	for _, id := range e.ChainIDs {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps 两条记录的链范围是否有交集，任意一条为空 (所有链通用) 时总是重叠
+func (e Entry) overlaps(other Entry) bool {
+	if len(e.ChainIDs) == 0 || len(other.ChainIDs) == 0 {
+		return true
+	}
+	for _, id := range other.ChainIDs {
+		if e.onChain(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameChains 两个链范围是否相同，忽略顺序和重复
+func sameChains(a, b []uint64) bool {
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	for _, id := range b {
+		if !slices.Contains(a, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// chainsLabel 用于错误信息的链范围
+func chainsLabel(ids []uint64) string {
+	if len(ids) == 0 {
+		return "所有链"
+	}
+	return fmt.Sprint(ids)
+}
+
+// splitList 拆分用 | 分隔的列表
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
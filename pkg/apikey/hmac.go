@@ -0,0 +1,154 @@
+package apikey
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 请求签名使用的请求头
+const (
+	HeaderKeyID     = "X-Api-Key-Id"
+	HeaderTimestamp = "X-Api-Timestamp" // unix 秒
+	HeaderNonce     = "X-Api-Nonce"
+	HeaderSignature = "X-Api-Signature" // hex(HMAC-SHA256(signingKey, canonical))
+)
+
+// DefaultWindow 时间戳允许的误差，也是 nonce 的保留时间
+const DefaultWindow = 5 * time.Minute
+
+// 验签失败的原因
+var (
+	ErrMissingHeaders   = errors.New("缺少 API 签名请求头")
+	ErrInvalidTimestamp = errors.New("请求时间戳超出允许范围")
+	ErrReplay           = errors.New("nonce 已经使用过，疑似重放请求")
+	ErrBadSignature     = errors.New("API 签名无效")
+)
+
+// CanonicalString 需要签名的内容:
+// METHOD \n 路径 (含查询参数) \n hex(sha256(body)) \n 时间戳 \n nonce
+func CanonicalString(method, path string, body []byte, timestamp, nonce string) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		hex.EncodeToString(bodyHash[:]),
+		timestamp,
+		nonce,
+	}, "\n")
+}
+
+// Sign 计算签名
+func Sign(signingKey []byte, canonical string) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest 客户端使用: 给 HTTP 请求加上签名请求头，会读取并重置 req.Body
+func SignRequest(req *http.Request, keyID, secret string) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	nonce, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	canonical := CanonicalString(req.Method, req.URL.RequestURI(), body, timestamp, nonce)
+
+	req.Header.Set(HeaderKeyID, keyID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Sign(DeriveSigningKey(secret), canonical))
+	return nil
+}
+
+// Verifier 服务端验签，带 nonce 防重放
+type Verifier struct {
+	store  *Store
+	window time.Duration
+
+	mu     sync.Mutex
+	nonces map[string]time.Time // keyID + nonce -> 过期时间
+}
+
+// NewVerifier 创建验签器，window 为 0 时使用 DefaultWindow
+func NewVerifier(store *Store, window time.Duration) *Verifier {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Verifier{store: store, window: window, nonces: make(map[string]time.Time)}
+}
+
+// Verify 验证请求签名，成功时返回对应的 key
+// path 需要包含查询参数，与客户端签名时的 RequestURI 一致
+func (v *Verifier) Verify(method, path string, header http.Header, body []byte) (*Key, error) {
+	keyID := header.Get(HeaderKeyID)
+	timestamp := header.Get(HeaderTimestamp)
+	nonce := header.Get(HeaderNonce)
+	sig := header.Get(HeaderSignature)
+	if keyID == "" || timestamp == "" || nonce == "" || sig == "" {
+		return nil, ErrMissingHeaders
+	}
+
+	// 时间戳必须在窗口内，窗口外的 nonce 已经被清理，不能再检查重放
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidTimestamp
+	}
+	now := time.Now()
+	if d := now.Sub(time.Unix(ts, 0)); d > v.window || d < -v.window {
+		return nil, ErrInvalidTimestamp
+	}
+
+	key, err := v.store.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, err := hex.DecodeString(key.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	expected := Sign(signingKey, CanonicalString(method, path, body, timestamp, nonce))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(sig))) {
+		return nil, ErrBadSignature
+	}
+
+	// 签名正确后再登记 nonce，避免攻击者用无效签名占用 nonce
+	if !v.useNonce(keyID+":"+nonce, now) {
+		return nil, ErrReplay
+	}
+	return key, nil
+}
+
+// useNonce 登记 nonce，已经存在时返回 false
+func (v *Verifier) useNonce(id string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for n, expiresAt := range v.nonces {
+		if now.After(expiresAt) {
+			delete(v.nonces, n)
+		}
+	}
+	if _, ok := v.nonces[id]; ok {
+		return false
+	}
+	// 时间戳最多比当前时间早或晚一个窗口，保留两个窗口可以覆盖所有仍然有效的请求
+	v.nonces[id] = now.Add(2 * v.window)
+	return true
+}
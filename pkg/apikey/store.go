@@ -0,0 +1,240 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 服务间调用的 API key
+//
+// 签发时生成 key ID 和 secret，secret 只在签发时显示一次，服务端不保存明文。
+// HMAC 验证必须在服务端持有签名密钥，所以保存的是 secret 的单向派生值 signingKey = HMAC-SHA256(secret, label)，
+// 客户端用同样的方式派生后签名。泄露 key 文件不会暴露 secret 明文，但可以伪造签名，文件仍然需要妥善保管 (权限 0600)。
+
+// DefaultPath 默认的 API key 文件
+const DefaultPath = "./apikeys.json"
+
+// signingKeyLabel 派生签名密钥使用的标签
+const signingKeyLabel = "learn-web3-go api key v1"
+
+// 查询失败的原因
+var (
+	ErrNotFound = errors.New("API key 不存在")
+	ErrRevoked  = errors.New("API key 已经吊销")
+	ErrExpired  = errors.New("API key 已经过期")
+)
+
+// Key 一个 API key 的记录
+type Key struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`   // 对应 API 服务的角色
+	Scopes     []string   `json:"scopes"` // 允许调用的接口范围，"*" 表示全部
+	SigningKey string     `json:"signingKey"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Active 检查 key 在 now 时刻是否可用
+func (k *Key) Active(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Store 持久化的 API key 列表
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	keys    map[string]*Key
+	modTime time.Time // 文件的修改时间，文件被命令行工具修改后自动重新读取
+}
+
+// Load 读取 API key 文件，文件不存在时返回空列表
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, keys: make(map[string]*Key)}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload 重新读取文件，调用方需要持有写锁或者 Store 还没有共享
+func (s *Store) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var keys []*Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("API key 文件 %s 格式有误: %w", s.path, err)
+	}
+	s.keys = make(map[string]*Key, len(keys))
+	for _, k := range keys {
+		s.keys[k.ID] = k
+	}
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// refresh 文件在外部被修改 (例如用命令行吊销) 时重新读取，失败时继续使用内存中的数据
+func (s *Store) refresh() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+	s.mu.RLock()
+	changed := !info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if !changed {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		log.Println("warn: 重新加载 API key 失败,", err)
+	}
+}
+
+// LoadFromEnv 读取 API_KEYS_FILE 指定的文件
+func LoadFromEnv() (*Store, error) {
+	path := os.Getenv("API_KEYS_FILE")
+	if path == "" {
+		path = DefaultPath
+	}
+	return Load(path)
+}
+
+// Save 写回文件
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// Issue 签发一个新的 API key，返回的 secret 只有这一次机会保存
+// ttl 为 0 表示永不过期
+func (s *Store) Issue(name, role string, scopes []string, ttl time.Duration) (*Key, string, error) {
+	s.refresh()
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	k := &Key{
+		ID:         "ak_" + id,
+		Name:       name,
+		Role:       role,
+		Scopes:     scopes,
+		SigningKey: hex.EncodeToString(DeriveSigningKey(secret)),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if ttl > 0 {
+		expiresAt := k.CreatedAt.Add(ttl)
+		k.ExpiresAt = &expiresAt
+	}
+
+	s.mu.Lock()
+	s.keys[k.ID] = k
+	s.mu.Unlock()
+	return k, secret, nil
+}
+
+// Revoke 吊销 API key，记录保留用于审计
+func (s *Store) Revoke(id string) error {
+	s.refresh()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if k.RevokedAt == nil {
+		now := time.Now().UTC()
+		k.RevokedAt = &now
+	}
+	return nil
+}
+
+// Get 查找可用的 API key
+func (s *Store) Get(id string) (*Key, error) {
+	s.refresh()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := k.Active(time.Now()); err != nil {
+		return nil, err
+	}
+	copied := *k
+	return &copied, nil
+}
+
+// List 返回所有 key 的副本，按创建时间排序
+func (s *Store) List() []Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, *k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys
+}
+
+// DeriveSigningKey 从 secret 派生 HMAC 签名密钥，客户端和服务端使用同样的方法
+func DeriveSigningKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingKeyLabel))
+	return mac.Sum(nil)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,347 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// 默认参数
+const (
+	DefaultChunk       = 2000    // 初始每次查询的区块数
+	DefaultMaxChunk    = 100_000 // 日志稀疏时最多放大到的区块数
+	DefaultTargetLogs  = 5000    // 每次查询期望返回的日志数，Infura 等服务商单次上限是 10000
+	DefaultConcurrency = 4
+	DefaultRetries     = 3
+)
+
+// limitExceededCode 部分节点返回的 "limit exceeded" 错误码 (EIP-1474)
+const limitExceededCode = -32005
+
+// 服务商在结果过多或范围过大时的错误信息，各家不统一，只能按关键字匹配
+var rangeErrorHints = []string{
+	"query returned more than", // Infura
+	"response size exceeded",   // Alchemy
+	"block range",              // Alchemy, BSC: exceed maximum block range
+	"is limited to",            // QuickNode
+	"too wide",                 // Ankr
+	"range is too large",
+	"range too large",
+	"max range",
+	"too many results",
+}
+
+// 限流也会返回 -32005，这类错误应该退避重试而不是拆分，例如:
+//   - Infura: "project ID request rate exceeded" (-32005)
+//   - Alchemy、QuickNode 等: "rate limit"、HTTP 429 "Too Many Requests"
+var rateLimitHints = []string{"rate limit", "rate exceeded", "too many requests", "429"}
+
+// 有的服务商会在错误中给出可行的范围，例如 Alchemy: "this block range should work: [0x1, 0x2]"
+var suggestedRange = regexp.MustCompile(`\[(0x[0-9a-fA-F]+),\s*(0x[0-9a-fA-F]+)\]`)
+
+// ErrRangeTooSmall 单个区块的日志也超过服务商的限制，无法继续拆分
+var ErrRangeTooSmall = errors.New("单个区块的日志超过服务商的限制")
+
+// IsRangeError 判断错误是否因为查询范围过大或结果过多，这类错误应该缩小范围重试而不是直接失败
+// 判断顺序: 先匹配限流关键字 (返回 false，按普通错误退避重试)，再看错误码 -32005，最后匹配范围过大的关键字
+func IsRangeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, hint := range rateLimitHints {
+		if strings.Contains(msg, hint) {
+			return false
+		}
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == limitExceededCode {
+		return true
+	}
+	for _, hint := range rangeErrorHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Backend 只需要 eth_getLogs，ethclient.Client、模拟链和合约绑定的 Filterer 都满足
+type Backend interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// Progress 回填进度，按顺序交付的区块计算
+type Progress struct {
+	Scanned uint64 // 已交付的区块数
+	Total   uint64
+	Logs    int    // 已交付的日志数
+	Chunk   uint64 // 当前每次查询的区块数
+}
+
+// Scanner 分段查询任意区块范围的历史日志
+//   - 服务商报告结果过多或范围过大时把这一段对半拆分，并缩小之后的查询范围
+//   - 日志稀疏时逐步放大查询范围，减少请求次数
+//   - 多个区间并发查询，但按区块和日志顺序交给调用方
+type Scanner struct {
+	backend Backend
+	query   ethereum.FilterQuery
+
+	minChunk    uint64
+	maxChunk    uint64
+	targetLogs  int
+	concurrency int
+	retries     int
+	progress    func(Progress)
+
+	mu    sync.Mutex
+	chunk uint64
+}
+
+// New 创建 Scanner，query 中的 FromBlock/ToBlock 会被忽略，范围由 Run 指定
+func New(backend Backend, query ethereum.FilterQuery) *Scanner {
+	return &Scanner{
+		backend:     backend,
+		query:       query,
+		minChunk:    1,
+		maxChunk:    DefaultMaxChunk,
+		targetLogs:  DefaultTargetLogs,
+		concurrency: DefaultConcurrency,
+		retries:     DefaultRetries,
+		chunk:       DefaultChunk,
+	}
+}
+
+// WithChunk 设置初始和最大的查询区块数，0 表示保持不变
+func (s *Scanner) WithChunk(initial, max uint64) *Scanner {
+	if max > 0 {
+		s.maxChunk = max
+	}
+	if initial > 0 {
+		s.chunk = initial
+	}
+	s.chunk = min(s.chunk, s.maxChunk)
+	return s
+}
+
+// WithTargetLogs 设置每次查询期望的日志数，结果明显少于它时放大范围，超过时缩小范围
+func (s *Scanner) WithTargetLogs(n int) *Scanner {
+	if n > 0 {
+		s.targetLogs = n
+	}
+	return s
+}
+
+// WithConcurrency 设置同时查询的区间数
+func (s *Scanner) WithConcurrency(n int) *Scanner {
+	if n > 0 {
+		s.concurrency = n
+	}
+	return s
+}
+
+// WithProgress 每交付一个区间后回调
+func (s *Scanner) WithProgress(fn func(Progress)) *Scanner {
+	s.progress = fn
+	return s
+}
+
+// segment 分配给一个 worker 的区间
+type segment struct {
+	seq        int
+	start, end uint64
+	logs       []types.Log
+	err        error
+}
+
+// Run 查询 [from, to] 范围内的日志，按区块和日志顺序逐条交给 handle
+// handle 返回错误时停止查询并返回该错误
+func (s *Scanner) Run(ctx context.Context, from, to uint64, handle func(types.Log) error) error {
+	if from > to {
+		return fmt.Errorf("起始区块 %d 大于结束区块 %d", from, to)
+	}
+	// 返回前取消还在进行的查询，并等待它们退出，Run 返回后不会再访问 backend
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// window 限制已分配但还没有交付的区间数量，前面的区间很慢时后面的结果不会无限堆积
+	window := make(chan struct{}, s.concurrency*2)
+	workers := make(chan struct{}, s.concurrency)
+	results := make(chan *segment)
+
+	// 按当前的区块数依次切出区间并发查询
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cursor, seq := from, 0
+		for {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			end := min(cursor+s.currentChunk()-1, to)
+			seg := &segment{seq: seq, start: cursor, end: end}
+			workers <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-workers; wg.Done() }()
+				seg.logs, seg.err = s.fetch(ctx, seg.start, seg.end)
+				select {
+				case results <- seg:
+				case <-ctx.Done():
+				}
+			}()
+			if end == to {
+				return
+			}
+			cursor, seq = end+1, seq+1
+		}
+	}()
+
+	// 按序号交付，先完成的区间暂存
+	pending := make(map[int]*segment)
+	next, delivered := 0, 0
+	for {
+		var seg *segment
+		select {
+		case seg = <-results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		pending[seg.seq] = seg
+		for {
+			seg, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if seg.err != nil {
+				return fmt.Errorf("查询区块 %d-%d 失败: %w", seg.start, seg.end, seg.err)
+			}
+			for _, l := range seg.logs {
+				if err := handle(l); err != nil {
+					return err
+				}
+			}
+			delivered += len(seg.logs)
+			if s.progress != nil {
+				s.progress(Progress{Scanned: seg.end - from + 1, Total: to - from + 1, Logs: delivered, Chunk: s.currentChunk()})
+			}
+			if seg.end == to {
+				return nil
+			}
+			next++
+			<-window
+		}
+	}
+}
+
+// fetch 查询一个区间，范围过大时对半拆分递归查询，返回排好序的日志
+func (s *Scanner) fetch(ctx context.Context, start, end uint64) ([]types.Log, error) {
+	q := s.query
+	q.FromBlock = new(big.Int).SetUint64(start)
+	q.ToBlock = new(big.Int).SetUint64(end)
+
+	var (
+		logs []types.Log
+		err  error
+	)
+	for attempt := 0; ; attempt++ {
+		logs, err = s.backend.FilterLogs(ctx, q)
+		if err == nil || IsRangeError(err) || attempt >= s.retries || ctx.Err() != nil {
+			break
+		}
+		// 网络错误等临时问题，退避后重试
+		select {
+		case <-time.After(time.Duration(1<<attempt) * 500 * time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if IsRangeError(err) {
+		size := end - start + 1
+		if size <= s.minChunk {
+			return nil, fmt.Errorf("%w: %v", ErrRangeTooSmall, err)
+		}
+		s.shrink(size, err)
+		mid := start + size/2 - 1
+		left, err := s.fetch(ctx, start, mid)
+		if err != nil {
+			return nil, err
+		}
+		right, err := s.fetch(ctx, mid+1, end)
+		if err != nil {
+			return nil, err
+		}
+		return append(left, right...), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.adjust(end-start+1, len(logs))
+	// 历史查询不应该出现被回滚的日志，这里按区块和日志顺序排列
+	kept := logs[:0]
+	for _, l := range logs {
+		if !l.Removed {
+			kept = append(kept, l)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].BlockNumber != kept[j].BlockNumber {
+			return kept[i].BlockNumber < kept[j].BlockNumber
+		}
+		return kept[i].Index < kept[j].Index
+	})
+	return kept, nil
+}
+
+func (s *Scanner) currentChunk() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chunk
+}
+
+// shrink 查询失败后缩小区块数，服务商给出了可行范围时直接使用
+func (s *Scanner) shrink(failed uint64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := failed / 2
+	if m := suggestedRange.FindStringSubmatch(err.Error()); m != nil {
+		lo, err1 := strconv.ParseUint(m[1][2:], 16, 64)
+		hi, err2 := strconv.ParseUint(m[2][2:], 16, 64)
+		if err1 == nil && err2 == nil && hi >= lo {
+			next = hi - lo + 1
+		}
+	}
+	s.chunk = max(min(s.chunk, next), s.minChunk)
+}
+
+// adjust 根据查询结果的数量调整区块数: 稀疏时翻倍，超过目标时按比例缩小
+func (s *Scanner) adjust(size uint64, found int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case found > s.targetLogs:
+		next := size * uint64(s.targetLogs) / uint64(found)
+		s.chunk = max(min(s.chunk, next), s.minChunk)
+	case found < s.targetLogs/4 && size >= s.chunk:
+		// 只有按当前大小完整查询过的区间才能说明日志稀疏，拆分出来的小区间不算
+		s.chunk = min(s.chunk*2, s.maxChunk)
+	}
+}
@@ -0,0 +1,236 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AddressResolver 把地址簿中的名字解析为地址，为 nil 时只接受十六进制地址
+type AddressResolver func(nameOrAddress string) (common.Address, error)
+
+// ParseArgs 按方法的参数类型把命令行字符串转换为 abi.Pack 需要的 Go 值
+//   - address: 十六进制地址，或者交给 resolve 解析的名字
+//   - int/uint: 十进制或 0x 开头的十六进制，超出位数时报错
+//   - bool: true/false
+//   - bytes/bytesN: 0x 开头的十六进制，bytesN 长度必须一致
+//   - 数组和 tuple: JSON，例如 [1,2,3]、["0xabc...",true]、{"to":"0x...","amount":"100"}
+//     tuple 可以按顺序写成数组，也可以按参数名写成对象；大整数建议写成字符串，避免 JSON 精度问题
+func ParseArgs(args abi.Arguments, inputs []string, resolve AddressResolver) ([]interface{}, error) {
+	if len(inputs) != len(args) {
+		return nil, fmt.Errorf("需要 %d 个参数, 实际 %d 个", len(args), len(inputs))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := ParseArg(arg.Type, inputs[i], resolve)
+		if err != nil {
+			name := arg.Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			return nil, fmt.Errorf("参数 %s (%s): %w", name, arg.Type, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// ParseArg 把一个字符串转换为 typ 对应的 Go 值
+func ParseArg(typ abi.Type, s string, resolve AddressResolver) (interface{}, error) {
+	var raw interface{} = s
+	switch typ.T {
+	case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		// 复合类型用 JSON 表示，数字保留为字符串，避免转换成 float64 丢失精度
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("无效的 JSON: %w", err)
+		}
+	}
+	v, err := convert(typ, raw, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// convert 把 JSON 解码出的值转换为 typ.GetType() 类型的 reflect.Value
+func convert(typ abi.Type, raw interface{}, resolve AddressResolver) (reflect.Value, error) {
+	goType := typ.GetType()
+	switch typ.T {
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s 需要 JSON 数组", typ)
+		}
+		var out reflect.Value
+		if typ.T == abi.SliceTy {
+			out = reflect.MakeSlice(goType, len(items), len(items))
+		} else {
+			if len(items) != typ.Size {
+				return reflect.Value{}, fmt.Errorf("%s 需要 %d 个元素, 实际 %d 个", typ, typ.Size, len(items))
+			}
+			out = reflect.New(goType).Elem()
+		}
+		for i, item := range items {
+			v, err := convert(*typ.Elem, item, resolve)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out.Index(i).Set(v)
+		}
+		return out, nil
+
+	case abi.TupleTy:
+		items, err := tupleItems(typ, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(goType).Elem()
+		for i, elem := range typ.TupleElems {
+			v, err := convert(*elem, items[i], resolve)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: %w", typ.TupleRawNames[i], err)
+			}
+			out.Field(i).Set(v)
+		}
+		return out, nil
+	}
+
+	// 以下都是标量，JSON 中的数字和布尔值统一按字符串处理
+	var s string
+	switch v := raw.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case json.Number:
+		s = v.String()
+	case bool:
+		s = strconv.FormatBool(v)
+	default:
+		return reflect.Value{}, fmt.Errorf("%s 不支持 %T", typ, raw)
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := parseInt(typ, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if goType == reflect.TypeOf(n) {
+			return reflect.ValueOf(n), nil
+		}
+		// 64 位以内的整数在 go-ethereum 中对应 int8/uint64 等原生类型
+		out := reflect.New(goType).Elem()
+		if typ.T == abi.IntTy {
+			out.SetInt(n.Int64())
+		} else {
+			out.SetUint(n.Uint64())
+		}
+		return out, nil
+
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的 bool: %s", s)
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy:
+		if common.IsHexAddress(s) {
+			return reflect.ValueOf(common.HexToAddress(s)), nil
+		}
+		if resolve == nil {
+			return reflect.Value{}, fmt.Errorf("无效的地址: %s", s)
+		}
+		addr, err := resolve(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(addr), nil
+
+	case abi.BytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的十六进制: %w", err)
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy, abi.FunctionTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的十六进制: %w", err)
+		}
+		out := reflect.New(goType).Elem()
+		if len(b) != out.Len() {
+			return reflect.Value{}, fmt.Errorf("%s 需要 %d 字节, 实际 %d 字节", typ, out.Len(), len(b))
+		}
+		reflect.Copy(out, reflect.ValueOf(b))
+		return out, nil
+	}
+	return reflect.Value{}, fmt.Errorf("不支持的类型 %s", typ)
+}
+
+// tupleItems 把 JSON 数组或对象按 tuple 成员的顺序排列
+func tupleItems(typ abi.Type, raw interface{}) ([]interface{}, error) {
+	switch v := raw.(type) {
+	case []interface{}:
+		if len(v) != len(typ.TupleElems) {
+			return nil, fmt.Errorf("%s 需要 %d 个成员, 实际 %d 个", typ, len(typ.TupleElems), len(v))
+		}
+		return v, nil
+	case map[string]interface{}:
+		items := make([]interface{}, len(typ.TupleElems))
+		for i, name := range typ.TupleRawNames {
+			item, ok := v[name]
+			if !ok {
+				return nil, fmt.Errorf("缺少成员 %s", name)
+			}
+			items[i] = item
+		}
+		if len(v) != len(items) {
+			return nil, fmt.Errorf("%s 有未知的成员", typ)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%s 需要 JSON 数组或对象", typ)
+}
+
+// parseInt 解析十进制或十六进制整数，并检查是否超出类型的位数
+func parseInt(typ abi.Type, s string) (*big.Int, error) {
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.ContainsAny(digits, "+-") {
+		return nil, fmt.Errorf("无效的整数: %s", s)
+	}
+	if neg {
+		n.Neg(n)
+	}
+
+	if typ.T == abi.UintTy {
+		if n.Sign() < 0 || n.BitLen() > typ.Size {
+			return nil, fmt.Errorf("%s 超出 %s 的范围", s, typ)
+		}
+		return n, nil
+	}
+	// intN 的范围是 [-2^(N-1), 2^(N-1)-1]
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("%s 超出 %s 的范围", s, typ)
+	}
+	return n, nil
+}
@@ -0,0 +1,174 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LoadABI 加载 ABI，source 可以是:
+//   - 内联的 ABI JSON，以 [ 开头
+//   - abi/ 目录下 .abi 格式的文件
+//   - Hardhat / Foundry 编译产物这类带 "abi" 字段的 JSON 文件
+func LoadABI(source string) (abi.ABI, error) {
+	data := []byte(strings.TrimSpace(source))
+	if !strings.HasPrefix(string(data), "[") {
+		var err error
+		if data, err = os.ReadFile(source); err != nil {
+			return abi.ABI{}, err
+		}
+	}
+	// 编译产物: {"abi": [...], "bytecode": ...}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err == nil && len(artifact.ABI) > 0 {
+		data = artifact.ABI
+	}
+	return abi.JSON(strings.NewReader(string(data)))
+}
+
+// FindABI 在 dir 中按顺序查找 <name>.abi 或 <name>.json，返回第一个找到的 ABI 和文件路径
+// name 可以是地址 (按小写查找) 或地址簿中的名字，空字符串会被跳过
+func FindABI(dir string, names ...string) (abi.ABI, string, error) {
+	var tried []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if common.IsHexAddress(name) {
+			name = strings.ToLower(name)
+		}
+		for _, ext := range []string{".abi", ".json"} {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err != nil {
+				tried = append(tried, path)
+				continue
+			}
+			parsed, err := LoadABI(path)
+			return parsed, path, err
+		}
+	}
+	return abi.ABI{}, "", fmt.Errorf("找不到 ABI 文件, 尝试过: %s", strings.Join(tried, ", "))
+}
+
+// Client 不依赖 abigen 绑定、运行时按 ABI 调用合约
+type Client struct {
+	ABI     abi.ABI
+	Address common.Address
+
+	contract *bind.BoundContract
+}
+
+// New 创建 Client
+func New(address common.Address, parsed abi.ABI, backend bind.ContractBackend) *Client {
+	return &Client{
+		ABI:      parsed,
+		Address:  address,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}
+}
+
+// Method 按名字或签名查找方法
+// 重载的方法在 go-ethereum 中名字会加上序号 (transfer0)，也可以用签名 transfer(address,uint256) 指定
+func (c *Client) Method(name string) (abi.Method, error) {
+	var matched []abi.Method
+	for _, m := range c.ABI.Methods {
+		if m.Sig == name {
+			return m, nil
+		}
+		if m.RawName == name {
+			matched = append(matched, m)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		if m, ok := c.ABI.Methods[name]; ok {
+			return m, nil
+		}
+		return abi.Method{}, fmt.Errorf("ABI 中没有方法 %s", name)
+	case 1:
+		return matched[0], nil
+	}
+	sigs := make([]string, len(matched))
+	for i, m := range matched {
+		sigs[i] = m.Sig
+	}
+	sort.Strings(sigs)
+	return abi.Method{}, fmt.Errorf("%s 有多个重载, 请使用签名指定: %s", name, strings.Join(sigs, ", "))
+}
+
+// Methods 按签名排序的全部方法
+func (c *Client) Methods() []abi.Method {
+	methods := make([]abi.Method, 0, len(c.ABI.Methods))
+	for _, m := range c.ABI.Methods {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Sig < methods[j].Sig })
+	return methods
+}
+
+// Call 通过 eth_call 调用方法，返回解码后的全部返回值
+func (c *Client) Call(opts *bind.CallOpts, method abi.Method, params ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	if err := c.contract.Call(opts, &out, method.Name, params...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Transact 发送交易调用方法，nonce、gas 和手续费按 auth 中的设置，没有设置时自动估算
+// payable 方法通过 auth.Value 附带 ETH
+func (c *Client) Transact(auth *bind.TransactOpts, method abi.Method, params ...interface{}) (*types.Transaction, error) {
+	if auth.Value != nil && auth.Value.Sign() > 0 && !method.IsPayable() {
+		return nil, fmt.Errorf("%s 不是 payable 方法, 不能附带 ETH", method.Sig)
+	}
+	return c.contract.Transact(auth, method.Name, params...)
+}
+
+// Event 解码后的事件
+type Event struct {
+	Name     string                 `json:"name"`
+	Args     map[string]interface{} `json:"args"`
+	LogIndex uint                   `json:"logIndex"`
+}
+
+// DecodeLogs 解码收据中属于这个合约、并且在 ABI 中有定义的事件
+func (c *Client) DecodeLogs(receipt *types.Receipt) []Event {
+	var events []Event
+	for _, l := range receipt.Logs {
+		if l.Address != c.Address || len(l.Topics) == 0 {
+			continue
+		}
+		ev, err := c.ABI.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]interface{})
+		if err := c.ABI.UnpackIntoMap(args, ev.Name, l.Data); err != nil {
+			continue
+		}
+		var indexed abi.Arguments
+		for _, arg := range ev.Inputs {
+			if arg.Indexed {
+				indexed = append(indexed, arg)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(args, indexed, l.Topics[1:]); err != nil {
+			continue
+		}
+		for k, v := range args {
+			args[k] = FormatValue(v)
+		}
+		events = append(events, Event{Name: ev.Name, Args: args, LogIndex: l.Index})
+	}
+	return events
+}
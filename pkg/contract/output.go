@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FormatOutputs 把方法返回值或事件参数转换为适合输出 JSON 的结构，key 为参数名，没有名字时为序号
+func FormatOutputs(args abi.Arguments, values []interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		name := strconv.Itoa(i)
+		if i < len(args) && args[i].Name != "" {
+			name = args[i].Name
+		}
+		out[name] = FormatValue(v)
+	}
+	return out
+}
+
+// FormatValue 把 ABI 解码出的值转换为 JSON 友好的形式
+//   - 大整数转为十进制字符串，避免 JavaScript 等工具丢失精度
+//   - bytes 和 bytesN 转为 0x 开头的十六进制
+//   - tuple 转为按成员名索引的对象
+func FormatValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case *big.Int:
+		return x.String()
+	case common.Address:
+		return x.Hex()
+	case common.Hash:
+		return x.Hex()
+	case []byte:
+		return hexutil.Encode(x)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			// go-ethereum 生成的 tuple 结构体用 json tag 保存原始的成员名
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = FormatValue(rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"learn-web3-go/pkg/contract"
+)
+
+// ErrUnlinked 字节码中还有未链接的库占位符 (__$...$__)
+var ErrUnlinked = errors.New("字节码包含未链接的库")
+
+// Artifact 部署需要的 ABI 和创建字节码
+type Artifact struct {
+	ABI      abi.ABI
+	Bytecode []byte
+}
+
+// LoadArtifact 读取 Hardhat 或 Foundry 的编译产物
+//   - Hardhat: {"abi": [...], "bytecode": "0x..."}
+//   - Foundry: {"abi": [...], "bytecode": {"object": "0x..."}}
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode json.RawMessage `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	if len(raw.ABI) == 0 || len(raw.Bytecode) == 0 {
+		return nil, fmt.Errorf("%s 缺少 abi 或 bytecode 字段", path)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(raw.ABI)))
+	if err != nil {
+		return nil, err
+	}
+
+	var bin string
+	if err := json.Unmarshal(raw.Bytecode, &bin); err != nil {
+		var foundry struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw.Bytecode, &foundry); err != nil {
+			return nil, fmt.Errorf("无法识别的 bytecode 格式: %w", err)
+		}
+		bin = foundry.Object
+	}
+	code, err := decodeBin(bin)
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{ABI: parsed, Bytecode: code}, nil
+}
+
+// LoadABIBin 读取 solc --abi / --bin 输出的两个文件，abiSource 也可以是内联的 ABI JSON
+func LoadABIBin(abiSource, binPath string) (*Artifact, error) {
+	parsed, err := contract.LoadABI(abiSource)
+	if err != nil {
+		return nil, err
+	}
+	bin, err := os.ReadFile(binPath)
+	if err != nil {
+		return nil, err
+	}
+	code, err := decodeBin(string(bin))
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{ABI: parsed, Bytecode: code}, nil
+}
+
+// decodeBin 解析十六进制字节码，0x 前缀可选
+func decodeBin(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "__") {
+		return nil, ErrUnlinked
+	}
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	code, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("无效的字节码: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, errors.New("字节码为空, 可能是接口或抽象合约")
+	}
+	return code, nil
+}
+
+// InitCode 创建字节码加上 ABI 编码的构造函数参数，CREATE2 的地址由它的哈希决定
+func (a *Artifact) InitCode(args ...interface{}) ([]byte, error) {
+	encoded, err := a.ABI.Pack("", args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码构造函数参数失败: %w", err)
+	}
+	return append(append([]byte{}, a.Bytecode...), encoded...), nil
+}
+
+// Payable 构造函数是否可以接收 ETH
+func (a *Artifact) Payable() bool {
+	return a.ABI.Constructor.IsPayable()
+}
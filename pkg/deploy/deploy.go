@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 确定性部署代理 (https://github.com/Arachnid/deterministic-deployment-proxy)
+// calldata = salt(32 字节) ++ initCode，使用 CREATE2 部署，主网和大部分测试网都在同一个地址
+var (
+	ProxyAddress = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+	// ProxyRuntime 代理的运行时代码，可以通过 GenesisAlloc 放到模拟链上
+	ProxyRuntime = common.FromHex("0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3")
+)
+
+var (
+	// ErrNoProxy 链上没有确定性部署代理
+	ErrNoProxy = errors.New("链上没有确定性部署代理")
+	// ErrAlreadyDeployed CREATE2 的目标地址上已经有代码，同一个 salt 和 initCode 只能部署一次
+	ErrAlreadyDeployed = errors.New("目标地址上已经有合约")
+	// ErrNoCode 交易成功但地址上没有代码，通常是构造函数返回了空的运行时代码
+	ErrNoCode = errors.New("部署后地址上没有代码")
+	// ErrDeployFailed 部署交易执行失败
+	ErrDeployFailed = errors.New("部署交易执行失败")
+)
+
+// Backend 部署需要发送交易和等待收据
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// CreateAddress CREATE 部署的地址: keccak256(rlp(sender, nonce)) 的后 20 字节
+func CreateAddress(sender common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(sender, nonce)
+}
+
+// Create2Address CREATE2 部署的地址: keccak256(0xff ++ deployer ++ salt ++ keccak256(initCode)) 的后 20 字节
+// 与 nonce 无关，只要 deployer、salt 和 initCode 相同，在任何链上都是同一个地址
+func Create2Address(deployer common.Address, salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, crypto.Keccak256(initCode))
+}
+
+// ParseSalt 0x 开头的按十六进制解析并左侧补零 (奇数位时在最前面补一个 0)，格式有误或超过 32 字节时返回错误；
+// 不以 0x 开头的字符串使用 keccak256 哈希，方便用一个名字作为 salt
+func ParseSalt(s string) ([32]byte, error) {
+	if !has0xPrefix(s) {
+		return crypto.Keccak256Hash([]byte(s)), nil
+	}
+	digits := s[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("salt 不是有效的十六进制: %s", s)
+	}
+	if len(b) > 32 {
+		return [32]byte{}, fmt.Errorf("salt 超过 32 字节: %d 字节", len(b))
+	}
+	return common.BytesToHash(b), nil
+}
+
+func has0xPrefix(s string) bool {
+	return len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// Deployment 已发送的部署交易
+type Deployment struct {
+	Address common.Address // 预测的合约地址
+	Tx      *types.Transaction
+}
+
+// Predict 预测 from 下一笔 CREATE 部署的地址，auth.Nonce 为空时使用 pending nonce
+func Predict(ctx context.Context, backend bind.ContractTransactor, auth *bind.TransactOpts) (common.Address, uint64, error) {
+	if auth.Nonce != nil {
+		return CreateAddress(auth.From, auth.Nonce.Uint64()), auth.Nonce.Uint64(), nil
+	}
+	nonce, err := backend.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		return common.Address{}, 0, err
+	}
+	return CreateAddress(auth.From, nonce), nonce, nil
+}
+
+// Create 使用 CREATE 部署，合约地址由发送者和 nonce 决定
+// 发送前固定 nonce，保证返回的地址与实际部署的地址一致
+func Create(ctx context.Context, auth *bind.TransactOpts, backend Backend, initCode []byte) (*Deployment, error) {
+	addr, nonce, err := Predict(ctx, backend, auth)
+	if err != nil {
+		return nil, err
+	}
+	opts := *auth
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	if opts.Context == nil {
+		opts.Context = ctx
+	}
+	// 构造函数参数已经编码在 initCode 中，这里使用空 ABI
+	deployed, tx, _, err := bind.DeployContract(&opts, abi.ABI{}, initCode, backend)
+	if err != nil {
+		return nil, err
+	}
+	if deployed != addr {
+		return nil, fmt.Errorf("部署地址 %s 与预测的 %s 不一致", deployed.Hex(), addr.Hex())
+	}
+	return &Deployment{Address: addr, Tx: tx}, nil
+}
+
+// Create2 通过确定性部署代理使用 CREATE2 部署，合约地址由 salt 和 initCode 决定
+// 注意合约中的 msg.sender 是代理地址而不是 auth.From，构造函数里设置 owner = msg.sender 的合约不适合这样部署
+func Create2(ctx context.Context, auth *bind.TransactOpts, backend Backend, salt [32]byte, initCode []byte) (*Deployment, error) {
+	code, err := backend.CodeAt(ctx, ProxyAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, ErrNoProxy
+	}
+	addr := Create2Address(ProxyAddress, salt, initCode)
+	if code, err := backend.CodeAt(ctx, addr, nil); err != nil {
+		return nil, err
+	} else if len(code) > 0 {
+		return &Deployment{Address: addr}, ErrAlreadyDeployed
+	}
+
+	opts := *auth
+	if opts.Context == nil {
+		opts.Context = ctx
+	}
+	proxy := bind.NewBoundContract(ProxyAddress, abi.ABI{}, backend, backend, backend)
+	tx, err := proxy.RawTransact(&opts, append(salt[:], initCode...))
+	if err != nil {
+		return nil, err
+	}
+	return &Deployment{Address: addr, Tx: tx}, nil
+}
+
+// Wait 等待部署交易上链，并确认合约地址上有代码
+func (d *Deployment) Wait(ctx context.Context, backend bind.DeployBackend) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, backend, d.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, ErrDeployFailed
+	}
+	// CREATE 部署的收据中有 ContractAddress，CREATE2 通过代理部署时为空
+	if d.Tx.To() == nil && receipt.ContractAddress != d.Address {
+		return receipt, fmt.Errorf("收据中的合约地址 %s 与预测的 %s 不一致", receipt.ContractAddress.Hex(), d.Address.Hex())
+	}
+	code, err := backend.CodeAt(ctx, d.Address, receipt.BlockNumber)
+	if err != nil {
+		return receipt, err
+	}
+	if len(code) == 0 {
+		return receipt, ErrNoCode
+	}
+	return receipt, nil
+}
@@ -0,0 +1,339 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"learn-web3-go/contracts/multicall"
+)
+
+// Address Multicall3 在主网和绝大多数测试网上的地址
+var Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// 分批的默认限制
+const (
+	// DefaultMaxCalldata 每批 aggregate3 的 calldata 上限，部分 RPC 服务商限制请求体大小
+	DefaultMaxCalldata = 128 * 1024
+	// DefaultMaxGas 每批的 gas 预算，geth 默认的 eth_call gas 上限是 50M
+	DefaultMaxGas = 25_000_000
+	// DefaultCallGas 单个调用的预估 gas，余额、授权这类读取通常不超过 3 万
+	DefaultCallGas = 100_000
+	// DefaultConcurrency 同时发出的 eth_call 数量
+	DefaultConcurrency = 4
+)
+
+// callOverhead aggregate3 中每个 Call3 除 callData 外的编码长度: 偏移量 + target + allowFailure + callData 偏移量和长度
+const callOverhead = 5 * 32
+
+var (
+	// ErrNotExecuted Batch 还没有执行时读取 Future
+	ErrNotExecuted = errors.New("multicall: batch 尚未执行")
+	// ErrCallFailed 调用 revert
+	ErrCallFailed = errors.New("multicall: 调用失败")
+	// ErrNoReturnData 调用成功但没有返回值，通常是目标地址没有合约代码
+	ErrNoReturnData = errors.New("multicall: 没有返回值, 目标地址可能不是合约")
+)
+
+// Future 批量调用中一个调用的结果，Batch 执行后才可以读取
+type Future[T any] struct {
+	done  bool
+	value T
+	err   error
+}
+
+// Get 返回解码后的值或者这个调用自己的错误
+func (f *Future[T]) Get() (T, error) {
+	if !f.done {
+		var zero T
+		return zero, ErrNotExecuted
+	}
+	return f.value, f.err
+}
+
+// Err 返回调用的错误，成功时为 nil
+func (f *Future[T]) Err() error {
+	_, err := f.Get()
+	return err
+}
+
+func (f *Future[T]) resolve(value T, err error) {
+	f.value, f.err, f.done = value, err, true
+}
+
+// call 排队中的一个调用
+type call struct {
+	target  common.Address
+	data    []byte
+	resolve func(data []byte, err error)
+}
+
+// Batch 收集多个只读调用，通过 Multicall3 的 aggregate3 批量执行
+// 调用数量较多时按 calldata 大小和 gas 预算拆成多批并发执行，单个调用失败只影响自己的 Future
+//
+// 不同批次可能在不同的区块执行，需要一致的结果时用 AtBlock 固定区块
+type Batch struct {
+	backend     bind.ContractCaller
+	address     common.Address
+	block       *big.Int
+	maxCalldata int
+	maxGas      uint64
+	callGas     uint64
+	concurrency int
+
+	calls []call
+}
+
+// New 创建 Batch，使用默认的 Multicall3 地址和分批限制
+func New(backend bind.ContractCaller) *Batch {
+	return &Batch{
+		backend:     backend,
+		address:     Address,
+		maxCalldata: DefaultMaxCalldata,
+		maxGas:      DefaultMaxGas,
+		callGas:     DefaultCallGas,
+		concurrency: DefaultConcurrency,
+	}
+}
+
+// WithAddress 使用其他地址的 Multicall3 (例如本地开发链上自己部署的)
+func (b *Batch) WithAddress(address common.Address) *Batch {
+	b.address = address
+	return b
+}
+
+// AtBlock 在指定区块执行，nil 表示最新区块
+func (b *Batch) AtBlock(block *big.Int) *Batch {
+	b.block = block
+	return b
+}
+
+// WithLimits 修改分批限制: 每批 calldata 字节数、每批 gas 预算和单个调用的预估 gas，0 表示保持不变
+func (b *Batch) WithLimits(maxCalldata int, maxGas, callGas uint64) *Batch {
+	if maxCalldata > 0 {
+		b.maxCalldata = maxCalldata
+	}
+	if maxGas > 0 {
+		b.maxGas = maxGas
+	}
+	if callGas > 0 {
+		b.callGas = callGas
+	}
+	return b
+}
+
+// WithConcurrency 修改同时执行的批次数量
+func (b *Batch) WithConcurrency(n int) *Batch {
+	if n > 0 {
+		b.concurrency = n
+	}
+	return b
+}
+
+// Len 排队中的调用数量
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Raw 添加一个原始调用，Future 的值是返回数据
+// 对没有代码的地址调用也会成功并返回空数据，由调用方判断
+func (b *Batch) Raw(target common.Address, data []byte) *Future[[]byte] {
+	return CallFunc(b, target, data, func(out []byte) ([]byte, error) { return out, nil })
+}
+
+// CallFunc 添加一个调用，执行成功后用 decode 解析返回数据
+func CallFunc[T any](b *Batch, target common.Address, data []byte, decode func([]byte) (T, error)) *Future[T] {
+	f := new(Future[T])
+	b.calls = append(b.calls, call{
+		target: target,
+		data:   data,
+		resolve: func(out []byte, err error) {
+			if err != nil {
+				var zero T
+				f.resolve(zero, err)
+				return
+			}
+			f.resolve(decode(out))
+		},
+	})
+	return f
+}
+
+// Call 按 ABI 打包调用 method，返回值只有一个且类型为 T
+// 打包失败时不会加入队列，Future 直接带上错误
+func Call[T any](b *Batch, target common.Address, contractABI *abi.ABI, method string, args ...interface{}) *Future[T] {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		f := new(Future[T])
+		var zero T
+		f.resolve(zero, fmt.Errorf("打包 %s 失败: %w", method, err))
+		return f
+	}
+	return CallFunc(b, target, data, func(out []byte) (T, error) {
+		var zero T
+		if len(out) == 0 {
+			return zero, ErrNoReturnData
+		}
+		values, err := contractABI.Unpack(method, out)
+		if err != nil {
+			return zero, err
+		}
+		if len(values) != 1 {
+			return zero, fmt.Errorf("%s 返回 %d 个值, 只支持 1 个", method, len(values))
+		}
+		v, ok := values[0].(T)
+		if !ok {
+			return zero, fmt.Errorf("%s 的返回类型是 %T, 不是 %T", method, values[0], zero)
+		}
+		return v, nil
+	})
+}
+
+// Execute 执行所有排队的调用并填充 Future，执行后队列清空，Batch 可以继续使用
+// 返回值只包含 RPC 层面的错误 (这一批的 Future 也会带上同样的错误)，单个调用 revert 不会返回错误 (Future 中是 ErrCallFailed)
+// 链上没有 Multicall3 时 (例如本地开发链) 退回逐个 eth_call
+func (b *Batch) Execute(ctx context.Context) error {
+	calls := b.calls
+	b.calls = nil
+	if len(calls) == 0 {
+		return nil
+	}
+
+	code, err := b.backend.CodeAt(ctx, b.address, b.block)
+	if err != nil {
+		for _, c := range calls {
+			c.resolve(nil, err)
+		}
+		return err
+	}
+	var chunks [][]call
+	if len(code) > 0 {
+		chunks = b.split(calls)
+	} else {
+		// 每个调用单独一批
+		for i := range calls {
+			chunks = append(chunks, calls[i:i+1])
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, b.concurrency)
+	)
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []call) {
+			defer func() { <-sem; wg.Done() }()
+			var err error
+			if len(code) == 0 {
+				err = b.direct(ctx, chunk[0])
+			} else {
+				err = b.aggregate(ctx, chunk)
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(chunk)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// split 按 calldata 大小和 gas 预算分批，单个超过限制的调用自己一批
+func (b *Batch) split(calls []call) [][]call {
+	var (
+		chunks [][]call
+		start  int
+		size   int
+		gas    uint64
+	)
+	for i, c := range calls {
+		n := callOverhead + (len(c.data)+31)/32*32
+		if i > start && (size+n > b.maxCalldata || gas+b.callGas > b.maxGas) {
+			chunks = append(chunks, calls[start:i])
+			start, size, gas = i, 0, 0
+		}
+		size += n
+		gas += b.callGas
+	}
+	return append(chunks, calls[start:])
+}
+
+// aggregate 通过 aggregate3 执行一批调用，allowFailure 全部为 true
+func (b *Batch) aggregate(ctx context.Context, chunk []call) error {
+	calls := make([]multicall.Multicall3Call3, len(chunk))
+	for i, c := range chunk {
+		calls[i] = multicall.Multicall3Call3{Target: c.target, AllowFailure: true, CallData: c.data}
+	}
+	var results []multicall.Multicall3Result
+	mc, err := multicall.NewMulticallCaller(b.address, b.backend)
+	if err == nil {
+		results, err = mc.Aggregate3(&bind.CallOpts{Context: ctx, BlockNumber: b.block}, calls)
+	}
+	if err == nil && len(results) != len(chunk) {
+		err = fmt.Errorf("multicall: 返回 %d 个结果, 预期 %d 个", len(results), len(chunk))
+	}
+	if err != nil {
+		for _, c := range chunk {
+			c.resolve(nil, err)
+		}
+		return err
+	}
+	for i, c := range chunk {
+		if results[i].Success {
+			c.resolve(results[i].ReturnData, nil)
+		} else {
+			c.resolve(nil, revertError(results[i].ReturnData))
+		}
+	}
+	return nil
+}
+
+// direct 没有 Multicall3 时直接 eth_call
+// 只有合约执行失败 (revert) 算作这个调用自己的失败 (ErrCallFailed)；网络错误、超时等 RPC 错误同样记录在 Future 中，
+// 并且返回给 Execute，避免调用方把节点暂时不可用当成合约没有这个方法
+func (b *Batch) direct(ctx context.Context, c call) error {
+	to := c.target
+	out, err := b.backend.CallContract(ctx, ethereum.CallMsg{To: &to, Data: c.data}, b.block)
+	if err != nil {
+		if isExecutionError(err) {
+			c.resolve(nil, fmt.Errorf("%w: %v", ErrCallFailed, err))
+			return nil
+		}
+		c.resolve(nil, err)
+		return err
+	}
+	c.resolve(out, nil)
+	return nil
+}
+
+// isExecutionError 判断 eth_call 的错误是否来自合约执行 (revert、无效指令)，而不是网络或节点错误
+func isExecutionError(err error) bool {
+	var revert rpc.DataError
+	if errors.As(err, &revert) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "execution reverted") || strings.Contains(msg, "invalid opcode") || strings.Contains(msg, "invalid jump")
+}
+
+// revertError 尽量解析 revert 原因 (Error(string))
+func revertError(data []byte) error {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return fmt.Errorf("%w: %s", ErrCallFailed, reason)
+	}
+	return ErrCallFailed
+}
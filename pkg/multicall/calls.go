@@ -0,0 +1,66 @@
+package multicall
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/contracts/erc20"
+	"learn-web3-go/contracts/multicall"
+)
+
+// 常用调用的 ABI，只在包初始化时解析一次
+var (
+	erc20ABI     = mustABI(erc20.ERC20MetaData.ABI)
+	multicallABI = mustABI(multicall.MulticallMetaData.ABI)
+)
+
+func mustABI(s string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return &parsed
+}
+
+// BalanceOf 代币余额
+func (b *Batch) BalanceOf(token, owner common.Address) *Future[*big.Int] {
+	return Call[*big.Int](b, token, erc20ABI, "balanceOf", owner)
+}
+
+// Allowance owner 给 spender 的授权额度
+func (b *Batch) Allowance(token, owner, spender common.Address) *Future[*big.Int] {
+	return Call[*big.Int](b, token, erc20ABI, "allowance", owner, spender)
+}
+
+// TotalSupply 代币总供应量
+func (b *Batch) TotalSupply(token common.Address) *Future[*big.Int] {
+	return Call[*big.Int](b, token, erc20ABI, "totalSupply")
+}
+
+// Decimals 代币精度，不兼容没有 decimals() 的代币，需要兼容时使用 token.Resolver
+func (b *Batch) Decimals(token common.Address) *Future[uint8] {
+	return Call[uint8](b, token, erc20ABI, "decimals")
+}
+
+// Symbol 代币符号，不兼容返回 bytes32 的代币，需要兼容时使用 token.Resolver
+func (b *Batch) Symbol(token common.Address) *Future[string] {
+	return Call[string](b, token, erc20ABI, "symbol")
+}
+
+// EthBalance 通过 Multicall3 的 getEthBalance 查询 ETH 余额
+// 这里和下面的查询由 Multicall3 合约自己实现，链上没有 Multicall3 时 Future 带上错误
+func (b *Batch) EthBalance(addr common.Address) *Future[*big.Int] {
+	return Call[*big.Int](b, b.address, multicallABI, "getEthBalance", addr)
+}
+
+// BlockNumber 执行调用的区块高度
+func (b *Batch) BlockNumber() *Future[*big.Int] {
+	return Call[*big.Int](b, b.address, multicallABI, "getBlockNumber")
+}
+
+// Timestamp 执行调用的区块时间戳 (秒)
+func (b *Batch) Timestamp() *Future[*big.Int] {
+	return Call[*big.Int](b, b.address, multicallABI, "getCurrentBlockTimestamp")
+}
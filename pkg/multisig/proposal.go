@@ -0,0 +1,100 @@
+package multisig
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// Proposal 待签名的 Safe 交易提案，保存成 JSON 文件在 owner 之间传递，离线收集签名
+type Proposal struct {
+	Safe       common.Address `json:"safe"`
+	ChainID    *hexutil.Big   `json:"chainId"`
+	Tx         *SafeTx        `json:"tx"`
+	SafeTxHash common.Hash    `json:"safeTxHash"`
+	Signatures []Signature    `json:"signatures"`
+}
+
+// NewProposal 创建提案并计算 safeTxHash
+func NewProposal(chainID *big.Int, safe common.Address, tx *SafeTx) *Proposal {
+	return &Proposal{
+		Safe:       safe,
+		ChainID:    (*hexutil.Big)(chainID),
+		Tx:         tx,
+		SafeTxHash: tx.Hash(chainID, safe),
+	}
+}
+
+// LoadProposal 读取提案文件，并重新计算哈希防止被篡改
+func LoadProposal(path string) (*Proposal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Proposal)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("提案文件格式有误: %w", err)
+	}
+	if p.Tx == nil || p.ChainID == nil {
+		return nil, fmt.Errorf("提案文件缺少交易内容")
+	}
+	if hash := p.Tx.Hash(p.ChainID.ToInt(), p.Safe); hash != p.SafeTxHash {
+		return nil, fmt.Errorf("提案哈希不匹配，文件中为 %s，重新计算为 %s", p.SafeTxHash.Hex(), hash.Hex())
+	}
+	for _, sig := range p.Signatures {
+		if err := p.verify(sig); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Save 写入提案文件
+func (p *Proposal) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Sign 使用 owner 的私钥签名并加入提案
+func (p *Proposal) Sign(user *model.User) error {
+	sig, err := SignHash(p.SafeTxHash, user)
+	if err != nil {
+		return err
+	}
+	return p.AddSignature(sig)
+}
+
+// AddSignature 校验签名后加入提案，同一个 owner 的旧签名会被替换
+func (p *Proposal) AddSignature(sig Signature) error {
+	if err := p.verify(sig); err != nil {
+		return err
+	}
+	for i := range p.Signatures {
+		if p.Signatures[i].Signer == sig.Signer {
+			p.Signatures[i] = sig
+			return nil
+		}
+	}
+	p.Signatures = append(p.Signatures, sig)
+	return nil
+}
+
+// verify 检查签名确实来自声明的 owner
+func (p *Proposal) verify(sig Signature) error {
+	signer, err := RecoverSigner(p.SafeTxHash, sig.Data)
+	if err != nil {
+		return err
+	}
+	if signer != sig.Signer {
+		return fmt.Errorf("签名与 %s 不匹配", sig.Signer.Hex())
+	}
+	return nil
+}
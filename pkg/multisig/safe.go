@@ -0,0 +1,208 @@
+package multisig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"learn-web3-go/contracts/safe"
+)
+
+// Safe 对 Safe 多签钱包合约的封装，backend 可以是 ethclient 也可以是模拟链
+type Safe struct {
+	Address  common.Address
+	contract *safe.Safe
+}
+
+// NewSafe 连接一个已经部署的 Safe (代理合约地址)
+func NewSafe(address common.Address, backend bind.ContractBackend) (*Safe, error) {
+	contract, err := safe.NewSafe(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Safe{Address: address, contract: contract}, nil
+}
+
+// Owners 读取所有 owner
+func (s *Safe) Owners(opts *bind.CallOpts) ([]common.Address, error) {
+	return s.contract.GetOwners(opts)
+}
+
+// Threshold 读取签名门限
+func (s *Safe) Threshold(opts *bind.CallOpts) (*big.Int, error) {
+	return s.contract.GetThreshold(opts)
+}
+
+// Nonce 读取下一笔交易的 nonce
+func (s *Safe) Nonce(opts *bind.CallOpts) (*big.Int, error) {
+	return s.contract.Nonce(opts)
+}
+
+// Version 读取合约版本，例如 1.3.0
+func (s *Safe) Version(opts *bind.CallOpts) (string, error) {
+	return s.contract.VERSION(opts)
+}
+
+// NewTx 使用当前链上的 nonce 构造一笔交易
+func (s *Safe) NewTx(opts *bind.CallOpts, to common.Address, value *big.Int, data []byte) (*SafeTx, error) {
+	nonce, err := s.Nonce(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewSafeTx(to, value, data, nonce), nil
+}
+
+// TxHash 让合约自己计算 safeTxHash，可以用来核对本地计算的结果
+func (s *Safe) TxHash(opts *bind.CallOpts, tx *SafeTx) (common.Hash, error) {
+	return s.contract.GetTransactionHash(opts, tx.To, tx.Value.ToInt(), tx.Data, tx.Operation,
+		tx.SafeTxGas.ToInt(), tx.BaseGas.ToInt(), tx.GasPrice.ToInt(), tx.GasToken, tx.RefundReceiver, tx.Nonce.ToInt())
+}
+
+// ApproveHash owner 在链上预先批准 safeTxHash，之后可以使用 ApprovedHashSignature
+func (s *Safe) ApproveHash(auth *bind.TransactOpts, hash common.Hash) (*types.Transaction, error) {
+	return s.contract.ApproveHash(auth, hash)
+}
+
+// CheckSignatures 检查签名是否来自不同的 owner 并且数量达到门限
+// sender 是 execTransaction 的发送者，它的预先批准签名不需要链上 approveHash
+func (s *Safe) CheckSignatures(opts *bind.CallOpts, hash common.Hash, sigs []Signature, sender common.Address) error {
+	owners, err := s.Owners(opts)
+	if err != nil {
+		return err
+	}
+	threshold, err := s.Threshold(opts)
+	if err != nil {
+		return err
+	}
+	isOwner := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+
+	seen := make(map[common.Address]bool)
+	for _, sig := range sigs {
+		signer, err := RecoverSigner(hash, sig.Data)
+		if err != nil {
+			return err
+		}
+		if signer != sig.Signer {
+			return fmt.Errorf("签名与 %s 不匹配", sig.Signer.Hex())
+		}
+		if !isOwner[signer] {
+			return fmt.Errorf("%s 不是 Safe 的 owner", signer.Hex())
+		}
+		if seen[signer] {
+			return fmt.Errorf("%s 重复签名", signer.Hex())
+		}
+		// 预先批准类型的签名需要链上已经 approveHash，或者 owner 本人发送交易
+		if sig.Data[64] == 1 && signer != sender {
+			approved, err := s.contract.ApprovedHashes(opts, signer, hash)
+			if err != nil {
+				return err
+			}
+			if approved.Sign() == 0 {
+				return fmt.Errorf("%s 还没有在链上批准这笔交易", signer.Hex())
+			}
+		}
+		seen[signer] = true
+	}
+
+	if big.NewInt(int64(len(seen))).Cmp(threshold) < 0 {
+		return fmt.Errorf("签名数量不足，需要 %s 个，当前 %d 个", threshold, len(seen))
+	}
+	return nil
+}
+
+// Exec 校验签名之后调用 execTransaction
+func (s *Safe) Exec(auth *bind.TransactOpts, tx *SafeTx, sigs []Signature) (*types.Transaction, error) {
+	opts := &bind.CallOpts{Context: auth.Context}
+	hash, err := s.TxHash(opts, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckSignatures(opts, hash, sigs, auth.From); err != nil {
+		return nil, err
+	}
+	return s.contract.ExecTransaction(auth, tx.To, tx.Value.ToInt(), tx.Data, tx.Operation,
+		tx.SafeTxGas.ToInt(), tx.BaseGas.ToInt(), tx.GasPrice.ToInt(), tx.GasToken, tx.RefundReceiver, PackSignatures(sigs))
+}
+
+// ExecResult 解析回执，判断 Safe 内部调用是否执行成功
+// 内部调用失败时 execTransaction 本身不会 revert，而是发出 ExecutionFailure 事件
+// v1.4 起 txHash 改成了 indexed，事件签名不变，这里只按 topic 判断以兼容各个版本
+func (s *Safe) ExecResult(receipt *types.Receipt) (bool, error) {
+	safeABI, err := safe.SafeMetaData.GetAbi()
+	if err != nil {
+		return false, err
+	}
+	success := safeABI.Events["ExecutionSuccess"].ID
+	failure := safeABI.Events["ExecutionFailure"].ID
+	for _, vLog := range receipt.Logs {
+		if vLog.Address != s.Address || len(vLog.Topics) == 0 {
+			continue
+		}
+		switch vLog.Topics[0] {
+		case success:
+			return true, nil
+		case failure:
+			return false, nil
+		}
+	}
+	return false, errors.New("回执中没有 Safe 执行结果事件")
+}
+
+// DeploySafe 通过 SafeProxyFactory 部署一个新的 Safe 代理并完成初始化
+// 先用 eth_call 模拟得到代理地址，再真正发送交易
+func DeploySafe(auth *bind.TransactOpts, backend bind.ContractBackend, factory, singleton common.Address,
+	owners []common.Address, threshold int64, saltNonce *big.Int) (common.Address, *types.Transaction, error) {
+	safeABI, err := safe.SafeMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	// setup(owners, threshold, to, data, fallbackHandler, paymentToken, payment, paymentReceiver)
+	initializer, err := safeABI.Pack("setup", owners, big.NewInt(threshold),
+		common.Address{}, []byte{}, common.Address{}, common.Address{}, big.NewInt(0), common.Address{})
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	factoryABI, err := safe.SafeProxyFactoryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	proxy, err := predictProxy(auth, backend, factoryABI, factory, singleton, initializer, saltNonce)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	contract, err := safe.NewSafeProxyFactory(factory, backend)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	tx, err := contract.CreateProxyWithNonce(auth, singleton, initializer, saltNonce)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return proxy, tx, nil
+}
+
+// predictProxy 模拟调用 createProxyWithNonce 拿到返回的代理地址
+func predictProxy(auth *bind.TransactOpts, backend bind.ContractBackend, factoryABI *abi.ABI,
+	factory, singleton common.Address, initializer []byte, saltNonce *big.Int) (common.Address, error) {
+	caller := bind.NewBoundContract(factory, *factoryABI, backend, nil, nil)
+	var out []interface{}
+	ctx := auth.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	err := caller.Call(&bind.CallOpts{From: auth.From, Context: ctx}, &out, "createProxyWithNonce", singleton, initializer, saltNonce)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
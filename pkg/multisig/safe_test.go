@@ -0,0 +1,282 @@
+package multisig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"learn-web3-go/contracts/safe"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// safeEnv 模拟链上部署好的 Safe: 单例合约 + SafeProxyFactory + 2/3 多签代理
+type safeEnv struct {
+	t       *testing.T
+	backend *simulated.Backend
+	client  simulated.Client
+	chainID *big.Int
+	owners  []*model.User
+	safe    *Safe
+}
+
+func newSafeEnv(t *testing.T) *safeEnv {
+	t.Helper()
+	owners := make([]*model.User, 3)
+	alloc := types.GenesisAlloc{}
+	for i := range owners {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		owners[i] = &model.User{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}
+		alloc[owners[i].Address] = types.Account{Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))}
+	}
+	backend := simulated.NewBackend(alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	env := &safeEnv{t: t, backend: backend, client: backend.Client(), owners: owners}
+	chainID, err := env.client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.chainID = chainID
+
+	// 部署 Safe v1.3.0 单例合约和代理工厂，创建字节码来自 abi/safe.bin 和 abi/safe_proxy_factory.bin
+	singleton, tx, _, err := safe.DeploySafe(env.auth(owners[0].PrivateKey), env.client)
+	if err != nil {
+		t.Fatal("部署单例合约失败: ", err)
+	}
+	env.mine(tx)
+	factory, tx, _, err := safe.DeploySafeProxyFactory(env.auth(owners[0].PrivateKey), env.client)
+	if err != nil {
+		t.Fatal("部署代理工厂失败: ", err)
+	}
+	env.mine(tx)
+
+	// 通过工厂创建 2/3 多签代理
+	addrs := []common.Address{owners[0].Address, owners[1].Address, owners[2].Address}
+	proxy, tx, err := DeploySafe(env.auth(owners[0].PrivateKey), env.client, factory, singleton, addrs, 2, big.NewInt(1))
+	if err != nil {
+		t.Fatal("部署 Safe 代理失败: ", err)
+	}
+	env.mine(tx)
+	if code, _ := env.client.CodeAt(context.Background(), proxy, nil); len(code) == 0 {
+		t.Fatal("预测的代理地址上没有代码")
+	}
+	if env.safe, err = NewSafe(proxy, env.client); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func (e *safeEnv) auth(key *ecdsa.PrivateKey) *bind.TransactOpts {
+	e.t.Helper()
+	auth, err := bind.NewKeyedTransactorWithChainID(key, e.chainID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	auth.Context = context.Background()
+	return auth
+}
+
+// mine 出块并确认交易执行成功
+func (e *safeEnv) mine(tx *types.Transaction) *types.Receipt {
+	e.t.Helper()
+	e.backend.Commit()
+	receipt, err := e.client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		e.t.Fatalf("交易 %s 执行失败", tx.Hash().Hex())
+	}
+	return receipt
+}
+
+// fund 给 Safe 转入 ETH
+func (e *safeEnv) fund(amount *big.Int) {
+	e.t.Helper()
+	ctx := context.Background()
+	from := e.owners[0]
+	nonce, err := e.client.PendingNonceAt(ctx, from.Address)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	head, err := e.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	tx, err := types.SignNewTx(from.PrivateKey, types.LatestSignerForChainID(e.chainID), &types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), big.NewInt(params.GWei)),
+		Gas:       100_000,
+		To:        &e.safe.Address,
+		Value:     amount,
+	})
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	if err := e.client.SendTransaction(ctx, tx); err != nil {
+		e.t.Fatal(err)
+	}
+	e.mine(tx)
+}
+
+func TestSafeInfo(t *testing.T) {
+	env := newSafeEnv(t)
+	opts := &bind.CallOpts{}
+
+	owners, err := env.safe.Owners(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 3 {
+		t.Fatalf("owner 数量 %d, 期望 3", len(owners))
+	}
+	threshold, err := env.safe.Threshold(opts)
+	if err != nil || threshold.Int64() != 2 {
+		t.Fatalf("门限 %v (%v), 期望 2", threshold, err)
+	}
+	nonce, err := env.safe.Nonce(opts)
+	if err != nil || nonce.Sign() != 0 {
+		t.Fatalf("nonce %v (%v), 期望 0", nonce, err)
+	}
+	if version, err := env.safe.Version(opts); err != nil || version != "1.3.0" {
+		t.Fatalf("版本 %q (%v), 期望 1.3.0", version, err)
+	}
+}
+
+func TestSafeTxHashMatchesContract(t *testing.T) {
+	env := newSafeEnv(t)
+	opts := &bind.CallOpts{}
+
+	tx := NewSafeTx(common.HexToAddress("0x000000000000000000000000000000000000dEaD"), big.NewInt(12345), []byte{0xde, 0xad, 0xbe, 0xef}, big.NewInt(7))
+	tx.Operation = OperationDelegateCall
+	tx.SafeTxGas = newBig(50_000)
+	tx.BaseGas = newBig(21_000)
+	tx.GasPrice = newBig(3)
+	tx.GasToken = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tx.RefundReceiver = common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	onchain, err := env.safe.TxHash(opts, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local := tx.Hash(env.chainID, env.safe.Address); local != onchain {
+		t.Fatalf("本地 safeTxHash %s 与合约 %s 不一致", local.Hex(), onchain.Hex())
+	}
+}
+
+func TestSafeProposeSignExec(t *testing.T) {
+	env := newSafeEnv(t)
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+	env.fund(big.NewInt(params.Ether))
+
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	amount := big.NewInt(params.Ether / 2)
+	tx, err := env.safe.NewTx(opts, recipient, amount, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := NewProposal(env.chainID, env.safe.Address, tx)
+
+	onchain, err := env.safe.TxHash(opts, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onchain != proposal.SafeTxHash {
+		t.Fatalf("本地 safeTxHash %s 与合约 %s 不一致", proposal.SafeTxHash.Hex(), onchain.Hex())
+	}
+
+	// owner1 离线签名，owner2 在链上 approveHash，提案文件在两人之间传递
+	path := filepath.Join(t.TempDir(), "proposal.json")
+	if err := proposal.Sign(env.owners[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := proposal.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if proposal, err = LoadProposal(path); err != nil {
+		t.Fatal(err)
+	}
+	approve, err := env.safe.ApproveHash(env.auth(env.owners[2].PrivateKey), proposal.SafeTxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.mine(approve)
+	if err := proposal.AddSignature(ApprovedHashSignature(env.owners[2].Address)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 签名不足时在发送前就被拒绝
+	if _, err := env.safe.Exec(env.auth(env.owners[0].PrivateKey), proposal.Tx, proposal.Signatures[:1]); err == nil {
+		t.Fatal("只有 1 个签名时应该拒绝执行")
+	}
+
+	// 由不是签名者的 owner0 发送 execTransaction
+	exec, err := env.safe.Exec(env.auth(env.owners[0].PrivateKey), proposal.Tx, proposal.Signatures)
+	if err != nil {
+		t.Fatal("执行失败: ", err)
+	}
+	ok, err := env.safe.ExecResult(env.mine(exec))
+	if err != nil || !ok {
+		t.Fatalf("Safe 内部调用失败: %v", err)
+	}
+	if balance, _ := env.client.BalanceAt(ctx, recipient, nil); balance.Cmp(amount) != 0 {
+		t.Fatalf("收款地址余额 %s, 期望 %s", balance, amount)
+	}
+	if nonce, _ := env.safe.Nonce(opts); nonce.Int64() != 1 {
+		t.Fatalf("执行后 nonce %s, 期望 1", nonce)
+	}
+}
+
+func TestSafeExecBySignerWithoutApproveHash(t *testing.T) {
+	env := newSafeEnv(t)
+	opts := &bind.CallOpts{}
+
+	// 调用 Safe 自身的 changeThreshold(3)，不需要转账
+	data := append(crypto.Keccak256([]byte("changeThreshold(uint256)"))[:4], common.LeftPadBytes([]byte{3}, 32)...)
+	tx, err := env.safe.NewTx(opts, env.safe.Address, nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := NewProposal(env.chainID, env.safe.Address, tx)
+	if err := proposal.Sign(env.owners[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	// 预先批准类型的签名如果不是发送者本人，必须先在链上 approveHash
+	sigs := append(proposal.Signatures, ApprovedHashSignature(env.owners[0].Address))
+	if _, err := env.safe.Exec(env.auth(env.owners[1].PrivateKey), proposal.Tx, sigs); err == nil {
+		t.Fatal("owner0 没有 approveHash 时应该拒绝执行")
+	}
+
+	// owner0 自己发送交易时不需要 approveHash
+	exec, err := env.safe.Exec(env.auth(env.owners[0].PrivateKey), proposal.Tx, sigs)
+	if err != nil {
+		t.Fatal("执行失败: ", err)
+	}
+	ok, err := env.safe.ExecResult(env.mine(exec))
+	if err != nil || !ok {
+		t.Fatalf("Safe 内部调用失败: %v", err)
+	}
+	if threshold, _ := env.safe.Threshold(opts); threshold.Int64() != 3 {
+		t.Fatalf("门限 %s, 期望 3", threshold)
+	}
+}
+
+func newBig(n int64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(n))
+}
@@ -0,0 +1,156 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/signature"
+)
+
+// Safe 交易的操作类型
+const (
+	OperationCall         uint8 = 0
+	OperationDelegateCall uint8 = 1
+)
+
+var (
+	// domainSeparatorTypeHash Safe v1.3.0 及以后版本的 EIP-712 域
+	domainSeparatorTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	// safeTxTypeHash SafeTx 结构体的类型哈希
+	safeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+)
+
+// SafeTx Safe 多签交易，字段与合约 execTransaction 的参数一一对应
+type SafeTx struct {
+	To             common.Address `json:"to"`
+	Value          *hexutil.Big   `json:"value"`
+	Data           hexutil.Bytes  `json:"data"`
+	Operation      uint8          `json:"operation"`
+	SafeTxGas      *hexutil.Big   `json:"safeTxGas"`
+	BaseGas        *hexutil.Big   `json:"baseGas"`
+	GasPrice       *hexutil.Big   `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          *hexutil.Big   `json:"nonce"`
+}
+
+// NewSafeTx 创建一笔普通调用的交易，不使用 gas 退款
+func NewSafeTx(to common.Address, value *big.Int, data []byte, nonce *big.Int) *SafeTx {
+	if value == nil {
+		value = new(big.Int)
+	}
+	return &SafeTx{
+		To:        to,
+		Value:     (*hexutil.Big)(value),
+		Data:      data,
+		Operation: OperationCall,
+		SafeTxGas: new(hexutil.Big),
+		BaseGas:   new(hexutil.Big),
+		GasPrice:  new(hexutil.Big),
+		Nonce:     (*hexutil.Big)(nonce),
+	}
+}
+
+// DomainSeparator 计算 Safe 的 EIP-712 域分隔符
+func DomainSeparator(chainID *big.Int, safe common.Address) common.Hash {
+	return crypto.Keccak256Hash(
+		domainSeparatorTypeHash.Bytes(),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+}
+
+// StructHash 计算 SafeTx 的结构体哈希
+func (tx *SafeTx) StructHash() common.Hash {
+	return crypto.Keccak256Hash(
+		safeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		word(tx.Value),
+		crypto.Keccak256(tx.Data),
+		common.LeftPadBytes([]byte{tx.Operation}, 32),
+		word(tx.SafeTxGas),
+		word(tx.BaseGas),
+		word(tx.GasPrice),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		word(tx.Nonce),
+	)
+}
+
+// Hash 计算需要 owner 签名的 safeTxHash = keccak256(0x19 0x01 domainSeparator structHash)
+func (tx *SafeTx) Hash(chainID *big.Int, safe common.Address) common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte{0x19, 0x01},
+		DomainSeparator(chainID, safe).Bytes(),
+		tx.StructHash().Bytes(),
+	)
+}
+
+// Signature 一个 owner 的签名
+type Signature struct {
+	Signer common.Address `json:"signer"`
+	Data   hexutil.Bytes  `json:"data"` // 65 字节 r || s || v
+}
+
+// SignHash 使用 owner 的私钥直接对 safeTxHash 签名 (EOA 签名，v = 27/28)
+func SignHash(hash common.Hash, user *model.User) (Signature, error) {
+	sig, err := signature.SignHash(hash, user)
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{Signer: user.Address, Data: sig}, nil
+}
+
+// ApprovedHashSignature 构造"预先批准"类型的签名，owner 需要先调用 approveHash
+// 或者 owner 本人就是 execTransaction 的发送者
+// 格式: r = owner 地址, s = 0, v = 1
+func ApprovedHashSignature(owner common.Address) Signature {
+	sig := make([]byte, 65)
+	copy(sig[12:32], owner.Bytes())
+	sig[64] = 1
+	return Signature{Signer: owner, Data: sig}
+}
+
+// RecoverSigner 从 EOA 签名中恢复 owner 地址，预先批准类型直接返回 r 中的地址
+func RecoverSigner(hash common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, errors.New("签名长度必须是 65 字节")
+	}
+	switch v := sig[64]; {
+	case v == 1:
+		return common.BytesToAddress(sig[12:32]), nil
+	case v == 27 || v == 28:
+		return signature.RecoverHash(hash, sig)
+	default:
+		return common.Address{}, fmt.Errorf("不支持的签名类型 v=%d", v)
+	}
+}
+
+// PackSignatures 按 owner 地址升序排序并拼接签名，这是 Safe 合约校验的要求
+func PackSignatures(sigs []Signature) []byte {
+	sorted := append([]Signature{}, sigs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Signer.Bytes(), sorted[j].Signer.Bytes()) < 0
+	})
+
+	packed := make([]byte, 0, len(sorted)*65)
+	for _, sig := range sorted {
+		packed = append(packed, sig.Data...)
+	}
+	return packed
+}
+
+// word 把数值编码成 32 字节
+func word(v *hexutil.Big) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return common.LeftPadBytes(v.ToInt().Bytes(), 32)
+}
@@ -0,0 +1,75 @@
+package nft
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/contracts/erc1155"
+)
+
+// InterfaceERC1155 ERC-1155 的 ERC-165 接口 ID
+var InterfaceERC1155 = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+
+// ERC-1155 转账事件的 topic0
+var (
+	TransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	TransferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// ExpandURI 替换 uri 中的 {id}
+// EIP-1155 规定替换为 64 位小写十六进制、左侧补 0、不带 0x，例如 id 314592 -> 000...004cce0
+func ExpandURI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// TokenURI 查询 id 的元数据地址并替换 {id}
+func TokenURI(opts *bind.CallOpts, token *erc1155.ERC1155, id *big.Int) (string, error) {
+	uri, err := token.Uri(opts, id)
+	if err != nil {
+		return "", err
+	}
+	return ExpandURI(uri, id), nil
+}
+
+// BalancesOf 用一次 balanceOfBatch 查询 owner 持有的多个 id 的数量，返回值与 ids 一一对应
+func BalancesOf(opts *bind.CallOpts, token *erc1155.ERC1155, owner common.Address, ids []*big.Int) ([]*big.Int, error) {
+	owners := make([]common.Address, len(ids))
+	for i := range owners {
+		owners[i] = owner
+	}
+	balances, err := token.BalanceOfBatch(opts, owners, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) != len(ids) {
+		return nil, fmt.Errorf("balanceOfBatch 返回 %d 个结果, 查询了 %d 个", len(balances), len(ids))
+	}
+	return balances, nil
+}
+
+// HoldingsOf 根据事件回放得到的 id 列表，用 balanceOfBatch 读取链上的实际数量，去掉余额为 0 的 id
+func HoldingsOf(opts *bind.CallOpts, token *erc1155.ERC1155, owner common.Address, candidates []Holding) ([]Holding, error) {
+	ids := make([]*big.Int, len(candidates))
+	for i, h := range candidates {
+		ids[i] = h.TokenID
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	balances, err := BalancesOf(opts, token, owner, ids)
+	if err != nil {
+		return nil, err
+	}
+	holdings := make([]Holding, 0, len(candidates))
+	for i, h := range candidates {
+		if balances[i].Sign() > 0 {
+			h.Balance = balances[i]
+			holdings = append(holdings, h)
+		}
+	}
+	return holdings, nil
+}
@@ -0,0 +1,107 @@
+package nft
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/contracts/erc721"
+)
+
+// ERC-165 接口 ID
+var (
+	InterfaceERC721           = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceERC721Metadata   = [4]byte{0x5b, 0x5e, 0x13, 0x9f}
+	InterfaceERC721Enumerable = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+)
+
+// TransferTopic ERC-20 和 ERC-721 的 Transfer 事件签名相同，topic0 都是这个值
+var TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// TransferKind 转账日志的类型
+type TransferKind int
+
+const (
+	KindUnknown       TransferKind = iota
+	KindERC20                      // value 不是 indexed: topic0 + from + to，数量在 data 中
+	KindERC721                     // tokenId 是 indexed: topic0 + from + to + tokenId，data 为空
+	KindERC1155Single              // ERC-1155 TransferSingle
+	KindERC1155Batch               // ERC-1155 TransferBatch
+)
+
+func (k TransferKind) String() string {
+	switch k {
+	case KindERC20:
+		return "ERC-20"
+	case KindERC721:
+		return "ERC-721"
+	case KindERC1155Single, KindERC1155Batch:
+		return "ERC-1155"
+	}
+	return "unknown"
+}
+
+// TransferKindOf 判断转账日志的类型
+// ERC-20 和 ERC-721 的 Transfer 事件签名一样，只能根据 indexed topic 的数量区分，
+// 用 ERC-20 的绑定解析 NFT 日志会得到错误的结果，反之亦然
+func TransferKindOf(l types.Log) TransferKind {
+	if len(l.Topics) == 0 {
+		return KindUnknown
+	}
+	switch l.Topics[0] {
+	case TransferTopic:
+		switch {
+		case len(l.Topics) == 3 && len(l.Data) == 32:
+			return KindERC20
+		case len(l.Topics) == 4 && len(l.Data) == 0:
+			return KindERC721
+		}
+	case TransferSingleTopic:
+		if len(l.Topics) == 4 {
+			return KindERC1155Single
+		}
+	case TransferBatchTopic:
+		if len(l.Topics) == 4 {
+			return KindERC1155Batch
+		}
+	}
+	return KindUnknown
+}
+
+// InterfaceChecker 实现了 ERC-165 supportsInterface 的合约绑定，ERC721 和 ERC1155 都满足
+type InterfaceChecker interface {
+	SupportsInterface(opts *bind.CallOpts, interfaceId [4]byte) (bool, error)
+}
+
+// SupportsInterface 通过 ERC-165 检查合约是否实现了接口，没有实现 ERC-165 时返回 false
+func SupportsInterface(opts *bind.CallOpts, token InterfaceChecker, id [4]byte) bool {
+	ok, err := token.SupportsInterface(opts, id)
+	return err == nil && ok
+}
+
+// Holding 持有的一个 NFT
+type Holding struct {
+	TokenID *big.Int
+	Balance *big.Int // ERC-721 固定为 1，ERC-1155 为持有的数量
+	// Block 最后一次转入的区块，从链上状态读取时为 0
+	Block uint64
+}
+
+// HoldingsEnumerable 通过 ERC721Enumerable 的 tokenOfOwnerByIndex 读取持有的 NFT，结果以链上状态为准
+func HoldingsEnumerable(opts *bind.CallOpts, token *erc721.ERC721, owner common.Address) ([]Holding, error) {
+	balance, err := token.BalanceOf(opts, owner)
+	if err != nil {
+		return nil, err
+	}
+	holdings := make([]Holding, 0, balance.Uint64())
+	for i := uint64(0); i < balance.Uint64(); i++ {
+		id, err := token.TokenOfOwnerByIndex(opts, owner, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, Holding{TokenID: id, Balance: big.NewInt(1)})
+	}
+	return holdings, nil
+}
@@ -0,0 +1,165 @@
+package nft
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"learn-web3-go/contracts/erc1155"
+	"learn-web3-go/contracts/erc721"
+	"learn-web3-go/pkg/backfill"
+)
+
+// Movement 一个 token id 的一次转移，ERC-1155 的批量转账会拆成多条
+type Movement struct {
+	Kind     TransferKind
+	Contract common.Address
+	Operator common.Address // 发起转账的地址，只有 ERC-1155 有
+	From     common.Address // 零地址表示铸造
+	To       common.Address // 零地址表示销毁
+	ID       *big.Int
+	Value    *big.Int // ERC-721 固定为 1
+	Block    uint64
+	TxHash   common.Hash
+	LogIndex uint
+}
+
+// 解析日志只需要 ABI，与合约地址无关
+var (
+	erc721Parser, _  = erc721.NewERC721Filterer(common.Address{}, nil)
+	erc1155Parser, _ = erc1155.NewERC1155Filterer(common.Address{}, nil)
+)
+
+// DecodeMovements 把 ERC-721 Transfer、ERC-1155 TransferSingle/TransferBatch 日志解析为按 id 拆分的转移
+// 其它日志 (包括 ERC-20 的 Transfer) 返回 nil
+func DecodeMovements(l types.Log) ([]Movement, error) {
+	base := Movement{
+		Kind:     TransferKindOf(l),
+		Contract: l.Address,
+		Block:    l.BlockNumber,
+		TxHash:   l.TxHash,
+		LogIndex: l.Index,
+	}
+	switch base.Kind {
+	case KindERC721:
+		event, err := erc721Parser.ParseTransfer(l)
+		if err != nil {
+			return nil, err
+		}
+		base.From, base.To, base.ID, base.Value = event.From, event.To, event.TokenId, big.NewInt(1)
+		return []Movement{base}, nil
+
+	case KindERC1155Single:
+		event, err := erc1155Parser.ParseTransferSingle(l)
+		if err != nil {
+			return nil, err
+		}
+		base.Operator, base.From, base.To, base.ID, base.Value = event.Operator, event.From, event.To, event.Id, event.Value
+		return []Movement{base}, nil
+
+	case KindERC1155Batch:
+		event, err := erc1155Parser.ParseTransferBatch(l)
+		if err != nil {
+			return nil, err
+		}
+		if len(event.Ids) != len(event.Values) {
+			return nil, fmt.Errorf("TransferBatch 的 ids 和 values 长度不一致: %d != %d", len(event.Ids), len(event.Values))
+		}
+		movements := make([]Movement, len(event.Ids))
+		for i := range event.Ids {
+			m := base
+			m.Operator, m.From, m.To, m.ID, m.Value = event.Operator, event.From, event.To, event.Ids[i], event.Values[i]
+			movements[i] = m
+		}
+		return movements, nil
+	}
+	return nil, nil
+}
+
+// FetchMovements 查询 [from, to] 区块范围内 contract 中转入或转出 owner 的全部 NFT 转移，按链上顺序排列
+// 同时支持 ERC-721 和 ERC-1155；chunk 为初始每次查询的区块数，0 表示使用 backfill 的默认值，查询时会根据结果自动调整
+func FetchMovements(ctx context.Context, backend bind.ContractFilterer, contract, owner common.Address, from, to, chunk uint64) ([]Movement, error) {
+	ownerTopic := common.BytesToHash(owner.Bytes())
+	erc1155Topics := []common.Hash{TransferSingleTopic, TransferBatchTopic}
+	// ERC-721: from/to 是 topic1/topic2；ERC-1155: topic1 是 operator，from/to 是 topic2/topic3
+	queries := [][][]common.Hash{
+		{{TransferTopic}, {ownerTopic}},
+		{{TransferTopic}, nil, {ownerTopic}},
+		{erc1155Topics, nil, {ownerTopic}},
+		{erc1155Topics, nil, nil, {ownerTopic}},
+	}
+
+	var logs []types.Log
+	for _, topics := range queries {
+		query := ethereum.FilterQuery{Addresses: []common.Address{contract}, Topics: topics}
+		err := backfill.New(backend, query).WithChunk(chunk, 0).Run(ctx, from, to, func(l types.Log) error {
+			logs = append(logs, l)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 按区块和日志顺序排列，自己转给自己的日志 from 和 to 两次查询都会返回，需要去重
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	var movements []Movement
+	seen := make(map[[2]uint64]bool)
+	for _, l := range logs {
+		key := [2]uint64{l.BlockNumber, uint64(l.Index)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		// 跳过无法解析的日志会让回放出的持仓少算或多算，直接返回错误
+		decoded, err := DecodeMovements(l)
+		if err != nil {
+			return nil, fmt.Errorf("解析日志 %s #%d 失败: %w", l.TxHash.Hex(), l.Index, err)
+		}
+		movements = append(movements, decoded...)
+	}
+	return movements, nil
+}
+
+// HoldingsFromEvents 回放 [from, to] 区块范围内转入和转出 owner 的转账事件，计算持有的 NFT 和数量
+// 只适用于范围覆盖了合约部署以来全部转账的情况
+func HoldingsFromEvents(ctx context.Context, backend bind.ContractFilterer, contract, owner common.Address, from, to, chunk uint64) ([]Holding, error) {
+	movements, err := FetchMovements(ctx, backend, contract, owner, from, to, chunk)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[string]*Holding)
+	for _, m := range movements {
+		h, ok := held[m.ID.String()]
+		if !ok {
+			h = &Holding{TokenID: m.ID, Balance: new(big.Int)}
+			held[m.ID.String()] = h
+		}
+		if m.From == owner {
+			h.Balance.Sub(h.Balance, m.Value)
+		}
+		if m.To == owner {
+			h.Balance.Add(h.Balance, m.Value)
+			h.Block = m.Block
+		}
+	}
+
+	holdings := make([]Holding, 0, len(held))
+	for _, h := range held {
+		if h.Balance.Sign() > 0 {
+			holdings = append(holdings, *h)
+		}
+	}
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].TokenID.Cmp(holdings[j].TokenID) < 0 })
+	return holdings, nil
+}
@@ -0,0 +1,395 @@
+package permit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"learn-web3-go/contracts/erc20permit"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/signature"
+)
+
+// EIP-2612 permit: 用户链下签名授权，由服务端代为提交，用户不需要支付 gas
+
+// 验证失败的原因
+var (
+	ErrExpired          = errors.New("permit 已经过期")
+	ErrNonceMismatch    = errors.New("permit nonce 与链上不一致，可能已经被使用")
+	ErrInvalidSigner    = errors.New("permit 签名者不是 owner")
+	ErrWrongSpender     = errors.New("permit 的 spender 不是 PermitRouter")
+	ErrDomainMismatch   = errors.New("无法得到与合约 DOMAIN_SEPARATOR 一致的 EIP-712 域")
+	ErrValueTooLow      = errors.New("permit 授权额度小于转账金额")
+	ErrTokenMismatch    = errors.New("permit 的代币地址不一致")
+	ErrMissingSignature = errors.New("permit 缺少签名")
+	ErrIncomplete       = errors.New("permit 缺少 value、nonce 或 deadline")
+)
+
+// Permit 一次授权，JSON 格式用于在用户和服务端之间传递
+type Permit struct {
+	Token     common.Address `json:"token"`
+	Owner     common.Address `json:"owner"`
+	Spender   common.Address `json:"spender"`
+	Value     *hexutil.Big   `json:"value"`
+	Nonce     *hexutil.Big   `json:"nonce"`
+	Deadline  *hexutil.Big   `json:"deadline"` // unix 时间戳 (秒)
+	Signature hexutil.Bytes  `json:"signature,omitempty"`
+}
+
+// LoadPermit 读取 permit 文件
+func LoadPermit(path string) (*Permit, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Permit)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("permit 文件格式有误: %w", err)
+	}
+	if err := p.complete(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Save 写入 permit 文件
+func (p *Permit) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// complete 检查数值字段是否齐全，用户提交的 JSON 可能缺字段
+func (p *Permit) complete() error {
+	if p.Value == nil || p.Nonce == nil || p.Deadline == nil {
+		return ErrIncomplete
+	}
+	return nil
+}
+
+// Token 支持 permit 的 ERC-20 代币
+type Token struct {
+	Address  common.Address
+	ChainID  *big.Int
+	backend  bind.ContractBackend
+	contract *erc20permit.ERC20Permit
+	domain   *apitypes.TypedDataDomain // 第一次查询后缓存
+}
+
+// NewToken 连接一个支持 permit 的代币合约
+func NewToken(address common.Address, chainID *big.Int, backend bind.ContractBackend) (*Token, error) {
+	contract, err := erc20permit.NewERC20Permit(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{Address: address, ChainID: chainID, backend: backend, contract: contract}, nil
+}
+
+// Nonce 读取 owner 下一次 permit 使用的 nonce
+func (t *Token) Nonce(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+	return t.contract.Nonces(opts, owner)
+}
+
+// DomainSeparator 读取合约的 DOMAIN_SEPARATOR
+func (t *Token) DomainSeparator(opts *bind.CallOpts) (common.Hash, error) {
+	return t.contract.DOMAINSEPARATOR(opts)
+}
+
+// Domain 确定代币签名使用的 EIP-712 域
+// 优先使用 EIP-5267 的 eip712Domain()，不支持时用 name() + version() 拼出来 (没有 version() 时依次尝试 "1" 和 "2")
+// 最终结果必须与合约的 DOMAIN_SEPARATOR 一致，否则签出来的 permit 一定会失败
+func (t *Token) Domain(opts *bind.CallOpts) (*apitypes.TypedDataDomain, error) {
+	if t.domain != nil {
+		return t.domain, nil
+	}
+	expected, err := t.DomainSeparator(opts)
+	if err != nil {
+		return nil, fmt.Errorf("读取 DOMAIN_SEPARATOR 失败: %w", err)
+	}
+
+	var candidates []apitypes.TypedDataDomain
+	if d, err := t.contract.Eip712Domain(opts); err == nil {
+		domain := apitypes.TypedDataDomain{}
+		if d.Fields[0]&0x01 != 0 {
+			domain.Name = d.Name
+		}
+		if d.Fields[0]&0x02 != 0 {
+			domain.Version = d.Version
+		}
+		if d.Fields[0]&0x04 != 0 {
+			domain.ChainId = (*math.HexOrDecimal256)(d.ChainId)
+		}
+		if d.Fields[0]&0x08 != 0 {
+			domain.VerifyingContract = d.VerifyingContract.Hex()
+		}
+		if d.Fields[0]&0x10 != 0 {
+			domain.Salt = hexutil.Encode(d.Salt[:])
+		}
+		candidates = append(candidates, domain)
+	} else {
+		name, err := t.contract.Name(opts)
+		if err != nil {
+			return nil, fmt.Errorf("读取 name 失败: %w", err)
+		}
+		versions := []string{"1", "2"}
+		if version, err := t.contract.Version(opts); err == nil {
+			versions = []string{version}
+		}
+		for _, version := range versions {
+			candidates = append(candidates, apitypes.TypedDataDomain{
+				Name:              name,
+				Version:           version,
+				ChainId:           (*math.HexOrDecimal256)(t.ChainID),
+				VerifyingContract: t.Address.Hex(),
+			})
+		}
+	}
+
+	for i := range candidates {
+		separator, err := signature.DomainSeparator(newTypedData(candidates[i], nil))
+		if err == nil && separator == expected {
+			t.domain = &candidates[i]
+			return t.domain, nil
+		}
+	}
+	return nil, ErrDomainMismatch
+}
+
+// NewPermit 使用链上当前的 nonce 构造一个未签名的 permit
+func (t *Token) NewPermit(opts *bind.CallOpts, owner, spender common.Address, value *big.Int, deadline time.Time) (*Permit, error) {
+	nonce, err := t.Nonce(opts, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &Permit{
+		Token:    t.Address,
+		Owner:    owner,
+		Spender:  spender,
+		Value:    (*hexutil.Big)(value),
+		Nonce:    (*hexutil.Big)(nonce),
+		Deadline: (*hexutil.Big)(big.NewInt(deadline.Unix())),
+	}, nil
+}
+
+// TypedData 生成 permit 的 EIP-712 数据，可以直接交给钱包 eth_signTypedData_v4 签名
+func (t *Token) TypedData(opts *bind.CallOpts, p *Permit) (*signature.TypedData, error) {
+	if err := p.complete(); err != nil {
+		return nil, err
+	}
+	if p.Token != t.Address {
+		return nil, ErrTokenMismatch
+	}
+	domain, err := t.Domain(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newTypedData(*domain, p), nil
+}
+
+// Hash 计算 permit 需要签名的哈希
+func (t *Token) Hash(opts *bind.CallOpts, p *Permit) (common.Hash, error) {
+	td, err := t.TypedData(opts, p)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return signature.TypedDataHash(td)
+}
+
+// Sign owner 对 permit 签名，结果写入 p.Signature
+func (t *Token) Sign(opts *bind.CallOpts, user *model.User, p *Permit) error {
+	if p.Owner != user.Address {
+		return ErrInvalidSigner
+	}
+	td, err := t.TypedData(opts, p)
+	if err != nil {
+		return err
+	}
+	sig, err := signature.SignTypedData(user, td)
+	if err != nil {
+		return err
+	}
+	p.Signature = sig
+	return nil
+}
+
+// Verify 检查用户提交的 permit: 未过期、nonce 与链上一致、签名来自 owner
+func (t *Token) Verify(opts *bind.CallOpts, p *Permit) error {
+	if err := p.complete(); err != nil {
+		return err
+	}
+	if len(p.Signature) == 0 {
+		return ErrMissingSignature
+	}
+	if p.Deadline.ToInt().Cmp(big.NewInt(time.Now().Unix())) < 0 {
+		return ErrExpired
+	}
+	nonce, err := t.Nonce(opts, p.Owner)
+	if err != nil {
+		return err
+	}
+	if nonce.Cmp(p.Nonce.ToInt()) != 0 {
+		return ErrNonceMismatch
+	}
+	return t.verifySigner(opts, p)
+}
+
+// verifySigner 检查签名来自 owner
+func (t *Token) verifySigner(opts *bind.CallOpts, p *Permit) error {
+	hash, err := t.Hash(opts, p)
+	if err != nil {
+		return err
+	}
+	signer, err := signature.RecoverHash(hash, p.Signature)
+	if err != nil {
+		return err
+	}
+	if signer != p.Owner {
+		return ErrInvalidSigner
+	}
+	return nil
+}
+
+// verifySubmitted permit 已经被别人抢先提交到链上时的检查: 链上 nonce 已经超过 permit 的 nonce、签名来自 owner、
+// spender 的额度足够 amount。满足时 Router 中的 permit 调用会失败并被忽略，transferFrom 使用已经生效的额度
+func (t *Token) verifySubmitted(opts *bind.CallOpts, p *Permit, amount *big.Int) error {
+	nonce, err := t.Nonce(opts, p.Owner)
+	if err != nil {
+		return err
+	}
+	if nonce.Cmp(p.Nonce.ToInt()) <= 0 {
+		return ErrNonceMismatch
+	}
+	if err := t.verifySigner(opts, p); err != nil {
+		return err
+	}
+	allowance, err := t.contract.Allowance(opts, p.Owner, p.Spender)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(amount) < 0 {
+		return ErrNonceMismatch
+	}
+	return nil
+}
+
+// Redeem 由 Router 的 operator (auth.From) 兑现 permit，把 amount 从 owner 转给 to
+// permit 的 spender 必须是 router。permit 和 transferFrom 在 Router 的同一次调用中执行，要么都成功要么都回滚；
+// 签名在发送前总是先验证，不会因为 owner 已有的额度而跳过；permit 已经被别人抢先提交时，签名有效并且额度足够也可以兑现
+func (t *Token) Redeem(auth *bind.TransactOpts, router common.Address, p *Permit, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	if err := p.complete(); err != nil {
+		return nil, err
+	}
+	if p.Token != t.Address {
+		return nil, ErrTokenMismatch
+	}
+	if p.Spender != router {
+		return nil, ErrWrongSpender
+	}
+	if p.Value.ToInt().Cmp(amount) < 0 {
+		return nil, ErrValueTooLow
+	}
+	ctx := auth.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+	err := t.Verify(callOpts, p)
+	if errors.Is(err, ErrNonceMismatch) {
+		err = t.verifySubmitted(callOpts, p, amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	operator, err := RouterOperator(ctx, t.backend, router)
+	if err != nil {
+		return nil, err
+	}
+	if operator != auth.From {
+		return nil, ErrNotOperator
+	}
+
+	// 签名拆成 v, r, s
+	sig := p.Signature
+	if len(sig) != 65 {
+		return nil, signature.ErrInvalidSignature
+	}
+	var r, s [32]byte
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	v := sig[64]
+	if v < 27 {
+		v += 27
+	}
+	data, err := routerABI.Pack("redeem", t.Address, p.Owner, p.Value.ToInt(), p.Deadline.ToInt(), v, r, s, to, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先模拟执行，transferFrom 失败或返回 false 时不发送
+	if _, err := t.backend.CallContract(ctx, ethereum.CallMsg{From: auth.From, To: &router, Data: data}, nil); err != nil {
+		return nil, fmt.Errorf("模拟兑现失败: %w", err)
+	}
+	return bind.NewBoundContract(router, routerABI, t.backend, t.backend, t.backend).RawTransact(auth, data)
+}
+
+// newTypedData 构造 Permit 的 EIP-712 数据，p 为空时只包含域 (用于计算域分隔符)
+func newTypedData(domain apitypes.TypedDataDomain, p *Permit) *signature.TypedData {
+	td := &signature.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainType(domain),
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain:      domain,
+	}
+	if p != nil {
+		td.Message = apitypes.TypedDataMessage{
+			"owner":    p.Owner.Hex(),
+			"spender":  p.Spender.Hex(),
+			"value":    p.Value.ToInt().String(),
+			"nonce":    p.Nonce.ToInt().String(),
+			"deadline": p.Deadline.ToInt().String(),
+		}
+	}
+	return td
+}
+
+// domainType 按域中实际存在的字段生成 EIP712Domain 类型
+func domainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
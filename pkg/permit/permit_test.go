@@ -0,0 +1,333 @@
+package permit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// governanceTokenABI 测试用到的 GovernanceToken 方法，mint 只有部署者可以调用
+var governanceTokenABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"mint","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+	]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// falseTokenInitCode 对任何调用都返回 32 字节 0 的合约，模拟 transferFrom 返回 false 而不是 revert 的代币
+// 运行时代码: PUSH1 0x20 PUSH1 0 RETURN
+func falseTokenInitCode() []byte {
+	runtime := common.FromHex("60206000f3")
+	size := byte(len(runtime))
+	init := []byte{0x60, size, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, size, 0x60, 0x00, 0xf3}
+	return append(init, runtime...)
+}
+
+// permitEnv 模拟链上部署好的 EIP-2612 代币和 PermitRouter
+// users[0] 部署代币和 Router (operator)，users[1] 持有代币并签名 permit，users[2] 是其它账户
+type permitEnv struct {
+	t       *testing.T
+	backend *simulated.Backend
+	client  simulated.Client
+	chainID *big.Int
+	users   []*model.User
+	token   *Token
+	erc20   *bind.BoundContract
+	router  common.Address
+}
+
+func newPermitEnv(t *testing.T) *permitEnv {
+	t.Helper()
+	users := make([]*model.User, 3)
+	alloc := types.GenesisAlloc{}
+	for i := range users {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		users[i] = &model.User{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}
+		alloc[users[i].Address] = types.Account{Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))}
+	}
+	backend := simulated.NewBackend(alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	env := &permitEnv{t: t, backend: backend, client: backend.Client(), users: users}
+	chainID, err := env.client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.chainID = chainID
+
+	// OP 的 GovernanceToken (OpenZeppelin ERC20Permit + ERC20Votes)，创建字节码来自
+	// optimism v1.9.3 op-e2e/bindings/governancetoken.go
+	bin, err := os.ReadFile("testdata/governance_token.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAddr, tx, erc20, err := bind.DeployContract(env.auth(users[0].PrivateKey), governanceTokenABI, common.FromHex(strings.TrimSpace(string(bin))), env.client)
+	if err != nil {
+		t.Fatal("部署代币失败: ", err)
+	}
+	env.mine(tx)
+	env.erc20 = erc20
+	tx, err = erc20.Transact(env.auth(users[0].PrivateKey), "mint", users[1].Address, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.mine(tx)
+
+	router, tx, err := DeployRouter(env.auth(users[0].PrivateKey), env.client)
+	if err != nil {
+		t.Fatal("部署 Router 失败: ", err)
+	}
+	env.mine(tx)
+	env.router = router
+
+	if env.token, err = NewToken(tokenAddr, chainID, env.client); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func (e *permitEnv) auth(key *ecdsa.PrivateKey) *bind.TransactOpts {
+	e.t.Helper()
+	auth, err := bind.NewKeyedTransactorWithChainID(key, e.chainID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	auth.Context = context.Background()
+	return auth
+}
+
+// mine 出块并返回收据，不检查交易状态
+func (e *permitEnv) mine(tx *types.Transaction) *types.Receipt {
+	e.t.Helper()
+	e.backend.Commit()
+	receipt, err := e.client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return receipt
+}
+
+// signedPermit owner (users[1]) 给 Router 签名额度为 value 的 permit
+func (e *permitEnv) signedPermit(value int64) *Permit {
+	e.t.Helper()
+	opts := &bind.CallOpts{}
+	p, err := e.token.NewPermit(opts, e.users[1].Address, e.router, big.NewInt(value), time.Now().Add(time.Hour))
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	if err := e.token.Sign(opts, e.users[1], p); err != nil {
+		e.t.Fatal(err)
+	}
+	return p
+}
+
+// rawRedeem 绕过 Token.Redeem 的检查直接调用 Router，固定 gas 以便让 revert 的交易也能上链
+func (e *permitEnv) rawRedeem(from *model.User, token common.Address, p *Permit, to common.Address, amount *big.Int) *types.Receipt {
+	e.t.Helper()
+	var r, s [32]byte
+	copy(r[:], p.Signature[:32])
+	copy(s[:], p.Signature[32:64])
+	data, err := routerABI.Pack("redeem", token, p.Owner, p.Value.ToInt(), p.Deadline.ToInt(), p.Signature[64], r, s, to, amount)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	auth := e.auth(from.PrivateKey)
+	auth.GasLimit = 300_000
+	tx, err := bind.NewBoundContract(e.router, routerABI, e.client, e.client, e.client).RawTransact(auth, data)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return e.mine(tx)
+}
+
+func (e *permitEnv) balance(account common.Address) *big.Int {
+	e.t.Helper()
+	var out []interface{}
+	if err := e.erc20.Call(&bind.CallOpts{}, &out, "balanceOf", account); err != nil {
+		e.t.Fatal(err)
+	}
+	return out[0].(*big.Int)
+}
+
+func TestRouterOperator(t *testing.T) {
+	env := newPermitEnv(t)
+	operator, err := RouterOperator(context.Background(), env.client, env.router)
+	if err != nil || operator != env.users[0].Address {
+		t.Fatalf("operator %s (%v), 期望 %s", operator.Hex(), err, env.users[0].Address.Hex())
+	}
+	// 没有 Router 的地址
+	if _, err := RouterOperator(context.Background(), env.client, env.users[2].Address); err == nil {
+		t.Fatal("普通地址不应该被识别为 Router")
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	env := newPermitEnv(t)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	p := env.signedPermit(300)
+
+	tx, err := env.token.Redeem(env.auth(env.users[0].PrivateKey), env.router, p, recipient, big.NewInt(250))
+	if err != nil {
+		t.Fatal("兑现失败: ", err)
+	}
+	if receipt := env.mine(tx); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("兑现交易执行失败")
+	}
+	if got := env.balance(recipient); got.Int64() != 250 {
+		t.Fatalf("收款地址余额 %s, 期望 250", got)
+	}
+	if got := env.balance(env.users[1].Address); got.Int64() != 750 {
+		t.Fatalf("owner 余额 %s, 期望 750", got)
+	}
+	// permit 和 transferFrom 在同一笔交易中执行，nonce 已经使用，额度剩余 50
+	if nonce, _ := env.token.Nonce(&bind.CallOpts{}, env.users[1].Address); nonce.Int64() != 1 {
+		t.Fatalf("nonce %s, 期望 1", nonce)
+	}
+	var out []interface{}
+	if err := env.erc20.Call(&bind.CallOpts{}, &out, "allowance", env.users[1].Address, env.router); err != nil || out[0].(*big.Int).Int64() != 50 {
+		t.Fatalf("剩余额度 %v (%v), 期望 50", out, err)
+	}
+}
+
+func TestRedeemRejects(t *testing.T) {
+	env := newPermitEnv(t)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	operator := env.auth(env.users[0].PrivateKey)
+	p := env.signedPermit(300)
+
+	// 别人冒充 owner 签名
+	forged := *p
+	if err := env.token.Sign(&bind.CallOpts{}, &model.User{Address: p.Owner, PrivateKey: env.users[2].PrivateKey}, &forged); err != nil {
+		t.Fatal(err)
+	}
+	unsigned := *p
+	unsigned.Signature = nil
+	otherSpender := *p
+	otherSpender.Spender = env.users[0].Address
+
+	tests := []struct {
+		name   string
+		auth   *bind.TransactOpts
+		permit *Permit
+		amount int64
+		want   error
+	}{
+		{"未签名", operator, &unsigned, 100, ErrMissingSignature},
+		{"伪造签名", operator, &forged, 100, ErrInvalidSigner},
+		{"spender 不是 Router", operator, &otherSpender, 100, ErrWrongSpender},
+		{"超过授权额度", operator, p, 301, ErrValueTooLow},
+		{"不是 operator", env.auth(env.users[2].PrivateKey), p, 100, ErrNotOperator},
+	}
+	for _, tc := range tests {
+		if _, err := env.token.Redeem(tc.auth, env.router, tc.permit, recipient, big.NewInt(tc.amount)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: 错误 %v, 期望 %v", tc.name, err, tc.want)
+		}
+	}
+	if got := env.balance(recipient); got.Sign() != 0 {
+		t.Fatalf("收款地址余额 %s, 期望 0", got)
+	}
+}
+
+func TestRouterRevertsForNonOperator(t *testing.T) {
+	env := newPermitEnv(t)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	p := env.signedPermit(300)
+
+	// 绕过 Token.Redeem 的检查直接调用，Router 自己也必须拒绝
+	if receipt := env.rawRedeem(env.users[2], env.token.Address, p, recipient, big.NewInt(100)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("不是 operator 的调用应该 revert")
+	}
+	if got := env.balance(recipient); got.Sign() != 0 {
+		t.Fatalf("收款地址余额 %s, 期望 0", got)
+	}
+	if nonce, _ := env.token.Nonce(&bind.CallOpts{}, p.Owner); nonce.Sign() != 0 {
+		t.Fatalf("nonce %s, permit 不应该被提交", nonce)
+	}
+}
+
+func TestRedeemAfterFrontRun(t *testing.T) {
+	env := newPermitEnv(t)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	p := env.signedPermit(300)
+
+	// 别人在兑现之前把 permit 提交到链上
+	var r, s [32]byte
+	copy(r[:], p.Signature[:32])
+	copy(s[:], p.Signature[32:64])
+	tx, err := env.token.contract.Permit(env.auth(env.users[2].PrivateKey), p.Owner, p.Spender, p.Value.ToInt(), p.Deadline.ToInt(), p.Signature[64], r, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt := env.mine(tx); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("抢先提交 permit 失败")
+	}
+
+	// Router 中的 permit 调用失败被忽略，transferFrom 使用已经生效的额度
+	tx, err = env.token.Redeem(env.auth(env.users[0].PrivateKey), env.router, p, recipient, big.NewInt(300))
+	if err != nil {
+		t.Fatal("兑现失败: ", err)
+	}
+	if receipt := env.mine(tx); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("兑现交易执行失败")
+	}
+	if got := env.balance(recipient); got.Int64() != 300 {
+		t.Fatalf("收款地址余额 %s, 期望 300", got)
+	}
+
+	// 额度用完之后不能再次兑现
+	if _, err := env.token.Redeem(env.auth(env.users[0].PrivateKey), env.router, p, recipient, big.NewInt(1)); !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("重复兑现: 错误 %v, 期望 ErrNonceMismatch", err)
+	}
+}
+
+func TestRouterRevertsOnBadToken(t *testing.T) {
+	env := newPermitEnv(t)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+	p := env.signedPermit(300)
+
+	// transferFrom 返回 false 的代币
+	falseToken, tx, _, err := bind.DeployContract(env.auth(env.users[0].PrivateKey), abi.ABI{}, falseTokenInitCode(), env.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.mine(tx)
+	if receipt := env.rawRedeem(env.users[0], falseToken, p, recipient, big.NewInt(100)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("transferFrom 返回 false 时应该 revert")
+	}
+
+	// 没有代码的地址: 调用总是成功并且没有返回值
+	noCode := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	if receipt := env.rawRedeem(env.users[0], noCode, p, recipient, big.NewInt(100)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("代币地址没有代码时应该 revert")
+	}
+
+	// 正常的代币仍然可以兑现
+	if receipt := env.rawRedeem(env.users[0], env.token.Address, p, recipient, big.NewInt(100)); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("兑现交易执行失败")
+	}
+	if got := env.balance(recipient); got.Int64() != 100 {
+		t.Fatalf("收款地址余额 %s, 期望 100", got)
+	}
+}
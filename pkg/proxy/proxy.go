@@ -0,0 +1,185 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 代理合约把实现合约地址等信息保存在固定的存储槽中
+var (
+	// EIP-1967: bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)，减 1 是为了让槽位没有已知的原像
+	ImplementationSlot = eip1967Slot("eip1967.proxy.implementation")
+	AdminSlot          = eip1967Slot("eip1967.proxy.admin")
+	BeaconSlot         = eip1967Slot("eip1967.proxy.beacon")
+
+	// OpenZeppelin 在 EIP-1967 之前 (zos 2.x) 使用的槽位，没有减 1，USDC 等早期代币仍在使用
+	LegacyImplementationSlot = crypto.Keccak256Hash([]byte("org.zeppelinos.proxy.implementation"))
+	LegacyAdminSlot          = crypto.Keccak256Hash([]byte("org.zeppelinos.proxy.admin"))
+)
+
+func eip1967Slot(name string) common.Hash {
+	h := crypto.Keccak256Hash([]byte(name)).Big()
+	return common.BigToHash(h.Sub(h, big.NewInt(1)))
+}
+
+// EIP-1167 最小代理的字节码: 前缀 ++ 实现合约地址 ++ 后缀，共 45 字节
+var (
+	minimalPrefix = common.FromHex("363d3d373d3d3d363d73")
+	minimalSuffix = common.FromHex("5af43d82803e903d91602b57fd5bf3")
+)
+
+// beaconImplementationCall beacon 合约的 implementation() 调用
+var beaconImplementationCall = crypto.Keccak256([]byte("implementation()"))[:4]
+
+// maxDepth Resolve 最多跟随的代理层数，避免循环
+const maxDepth = 5
+
+var (
+	// ErrNotContract 地址上没有代码
+	ErrNotContract = errors.New("地址上没有合约代码")
+	// ErrTooDeep 代理嵌套层数超过 maxDepth，可能存在循环
+	ErrTooDeep = errors.New("代理嵌套层数过多")
+)
+
+// Kind 代理的类型
+type Kind int
+
+const (
+	KindNone          Kind = iota // 不是代理，或者是无法识别的代理
+	KindEIP1967                   // EIP-1967 透明代理或 UUPS 代理
+	KindEIP1967Beacon             // EIP-1967 beacon 代理，实现合约地址由 beacon 合约返回
+	KindOZLegacy                  // OpenZeppelin zos 旧版代理
+	KindEIP1167                   // EIP-1167 最小代理 (clone)，实现合约地址写在字节码中，不可升级
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindEIP1967:
+		return "EIP-1967"
+	case KindEIP1967Beacon:
+		return "EIP-1967 Beacon"
+	case KindOZLegacy:
+		return "OpenZeppelin Legacy"
+	case KindEIP1167:
+		return "EIP-1167 Minimal Proxy"
+	}
+	return "none"
+}
+
+// Backend 检测代理需要读取代码、存储和调用 beacon，ethclient.Client 和模拟链都满足
+type Backend interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Info 代理合约的信息，没有的字段为零地址
+type Info struct {
+	Kind           Kind
+	Proxy          common.Address
+	Implementation common.Address
+	Admin          common.Address // 透明代理的管理员，UUPS 代理通常为空
+	Beacon         common.Address
+}
+
+// IsProxy 是否识别为代理
+func (i *Info) IsProxy() bool {
+	return i.Kind != KindNone
+}
+
+// Detect 检测 addr 是否是代理合约，block 为 nil 时读取最新状态
+func Detect(ctx context.Context, backend Backend, addr common.Address, block *big.Int) (*Info, error) {
+	code, err := backend.CodeAt(ctx, addr, block)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, ErrNotContract
+	}
+	info := &Info{Proxy: addr}
+
+	// EIP-1167 不使用存储，直接从字节码中取地址
+	if impl, ok := parseMinimalProxy(code); ok {
+		info.Kind, info.Implementation = KindEIP1167, impl
+		return info, nil
+	}
+
+	readSlot := func(slot common.Hash) (common.Address, error) {
+		value, err := backend.StorageAt(ctx, addr, slot, block)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("读取槽位 %s 失败: %w", slot.Hex(), err)
+		}
+		return common.BytesToAddress(value), nil
+	}
+
+	if info.Implementation, err = readSlot(ImplementationSlot); err != nil {
+		return nil, err
+	}
+	if info.Implementation != (common.Address{}) {
+		info.Kind = KindEIP1967
+		info.Admin, err = readSlot(AdminSlot)
+		return info, err
+	}
+
+	if info.Beacon, err = readSlot(BeaconSlot); err != nil {
+		return nil, err
+	}
+	if info.Beacon != (common.Address{}) {
+		info.Kind = KindEIP1967Beacon
+		beacon := info.Beacon
+		out, err := backend.CallContract(ctx, ethereum.CallMsg{To: &beacon, Data: beaconImplementationCall}, block)
+		if err != nil {
+			return nil, fmt.Errorf("查询 beacon %s 的实现合约失败: %w", beacon.Hex(), err)
+		}
+		if len(out) < 32 {
+			return nil, fmt.Errorf("beacon %s 没有返回实现合约地址", beacon.Hex())
+		}
+		info.Implementation = common.BytesToAddress(out[:32])
+		info.Admin, err = readSlot(AdminSlot)
+		return info, err
+	}
+
+	if info.Implementation, err = readSlot(LegacyImplementationSlot); err != nil {
+		return nil, err
+	}
+	if info.Implementation != (common.Address{}) {
+		info.Kind = KindOZLegacy
+		info.Admin, err = readSlot(LegacyAdminSlot)
+		return info, err
+	}
+	return info, nil
+}
+
+// Resolve 沿着代理链找到最终的实现合约，例如指向 EIP-1967 代理的 clone
+// 返回经过的每一层代理，addr 不是代理时返回 addr 本身和空列表
+func Resolve(ctx context.Context, backend Backend, addr common.Address, block *big.Int) (common.Address, []*Info, error) {
+	var chain []*Info
+	for range maxDepth {
+		info, err := Detect(ctx, backend, addr, block)
+		if err != nil {
+			return common.Address{}, chain, err
+		}
+		if !info.IsProxy() {
+			return addr, chain, nil
+		}
+		chain = append(chain, info)
+		addr = info.Implementation
+	}
+	return common.Address{}, chain, ErrTooDeep
+}
+
+// parseMinimalProxy 识别 EIP-1167 最小代理的字节码
+func parseMinimalProxy(code []byte) (common.Address, bool) {
+	if len(code) != len(minimalPrefix)+common.AddressLength+len(minimalSuffix) ||
+		!bytes.HasPrefix(code, minimalPrefix) || !bytes.HasSuffix(code, minimalSuffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(minimalPrefix) : len(minimalPrefix)+common.AddressLength]), true
+}
@@ -0,0 +1,227 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EIP-1271 合约钱包签名 / ERC-6492 未部署合约钱包签名
+
+// ERC1271MagicValue isValidSignature(bytes32,bytes) 验证通过时返回的值，即函数选择器本身
+var ERC1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+// ERC6492MagicSuffix ERC-6492 包装签名末尾的 32 字节标记
+var ERC6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+// ErrSimulateUnsupported 节点不支持 eth_simulateV1，无法验证未部署钱包的签名
+var ErrSimulateUnsupported = errors.New("节点不支持 eth_simulateV1，无法验证未部署合约钱包的签名")
+
+const erc1271ABI = `[{"inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+
+var (
+	erc1271, _ = abi.JSON(strings.NewReader(erc1271ABI))
+
+	// ERC-6492 包装格式: abi.encode(address factory, bytes factoryCalldata, bytes signature) ++ magicSuffix
+	erc6492Args = abi.Arguments{
+		{Type: mustType("address")},
+		{Type: mustType("bytes")},
+		{Type: mustType("bytes")},
+	}
+)
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// Backend 验证合约钱包签名需要的链上接口，*ethclient.Client 和模拟链的 Client 都满足
+type Backend interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// simulator 支持 eth_simulateV1 的客户端
+type simulator interface {
+	SimulateV1(ctx context.Context, opts ethclient.SimulateOptions, blockNrOrHash *rpc.BlockNumberOrHash) ([]ethclient.SimulateBlockResult, error)
+}
+
+// ERC6492Signature 解包后的 ERC-6492 签名
+type ERC6492Signature struct {
+	Factory         common.Address // 部署钱包的工厂合约
+	FactoryCalldata []byte         // 调用工厂部署钱包的 calldata
+	Signature       []byte         // 钱包部署后 isValidSignature 使用的原始签名
+}
+
+// IsERC6492Signature 判断签名是否是 ERC-6492 包装格式
+func IsERC6492Signature(sig []byte) bool {
+	return len(sig) > len(ERC6492MagicSuffix) && bytes.HasSuffix(sig, ERC6492MagicSuffix)
+}
+
+// ParseERC6492Signature 解包 ERC-6492 签名
+func ParseERC6492Signature(sig []byte) (*ERC6492Signature, error) {
+	if !IsERC6492Signature(sig) {
+		return nil, fmt.Errorf("%w: 不是 ERC-6492 签名", ErrInvalidSignature)
+	}
+	values, err := erc6492Args.Unpack(sig[:len(sig)-len(ERC6492MagicSuffix)])
+	if err != nil {
+		return nil, fmt.Errorf("%w: ERC-6492 解码失败 %v", ErrInvalidSignature, err)
+	}
+	return &ERC6492Signature{
+		Factory:         values[0].(common.Address),
+		FactoryCalldata: values[1].([]byte),
+		Signature:       values[2].([]byte),
+	}, nil
+}
+
+// Encode 按 ERC-6492 格式包装签名
+func (s *ERC6492Signature) Encode() ([]byte, error) {
+	packed, err := erc6492Args.Pack(s.Factory, s.FactoryCalldata, s.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return append(packed, ERC6492MagicSuffix...), nil
+}
+
+// IsContract 判断地址上是否部署了合约
+func IsContract(ctx context.Context, backend Backend, address common.Address) (bool, error) {
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// IsValidSignature 调用已部署合约钱包的 isValidSignature，返回值等于 0x1626ba7e 时签名有效
+// 合约 revert 视为签名无效，不返回错误
+func IsValidSignature(ctx context.Context, backend Backend, wallet common.Address, hash common.Hash, sig []byte) (bool, error) {
+	input, err := erc1271.Pack("isValidSignature", hash, sig)
+	if err != nil {
+		return false, err
+	}
+	output, err := backend.CallContract(ctx, ethereum.CallMsg{To: &wallet, Data: input}, nil)
+	if err != nil {
+		var revert rpc.DataError
+		if errors.As(err, &revert) || strings.Contains(err.Error(), "execution reverted") {
+			return false, nil
+		}
+		return false, err
+	}
+	return isMagicValue(output), nil
+}
+
+// isMagicValue 检查 isValidSignature 的返回值 (bytes4 左对齐到 32 字节)
+func isMagicValue(output []byte) bool {
+	return len(output) >= 32 && bytes.Equal(output[:4], ERC1271MagicValue[:])
+}
+
+// Verifier 同时支持 EOA、EIP-1271 合约钱包和 ERC-6492 未部署钱包的签名验证
+type Verifier struct {
+	backend Backend
+}
+
+// NewVerifier 创建验证器，backend 可以是 *ethclient.Client 或模拟链的 Client
+func NewVerifier(backend Backend) *Verifier {
+	return &Verifier{backend: backend}
+}
+
+// VerifyHash 验证 hash 的签名是否来自 signer
+//  1. ERC-6492 包装签名: 钱包已部署时直接用原始签名调用 isValidSignature,
+//     未部署时用 eth_simulateV1 在同一个模拟区块中先调用工厂部署再调用 isValidSignature
+//  2. signer 地址上有合约代码: 调用 isValidSignature (EIP-1271)
+//  3. 否则按 EOA 的 ecrecover 验证
+func (v *Verifier) VerifyHash(ctx context.Context, signer common.Address, hash common.Hash, sig []byte) (bool, error) {
+	if IsERC6492Signature(sig) {
+		wrapped, err := ParseERC6492Signature(sig)
+		if err != nil {
+			return false, err
+		}
+		deployed, err := IsContract(ctx, v.backend, signer)
+		if err != nil {
+			return false, err
+		}
+		if deployed {
+			return IsValidSignature(ctx, v.backend, signer, hash, wrapped.Signature)
+		}
+		return v.verifyCounterfactual(ctx, signer, hash, wrapped)
+	}
+
+	deployed, err := IsContract(ctx, v.backend, signer)
+	if err != nil {
+		return false, err
+	}
+	if deployed {
+		return IsValidSignature(ctx, v.backend, signer, hash, sig)
+	}
+
+	recovered, err := RecoverHash(hash, sig)
+	if err != nil {
+		return false, err
+	}
+	return recovered == signer, nil
+}
+
+// VerifyPersonalMessage 验证 personal_sign 签名，支持合约钱包
+func (v *Verifier) VerifyPersonalMessage(ctx context.Context, signer common.Address, message, sig []byte) (bool, error) {
+	return v.VerifyHash(ctx, signer, PersonalMessageHash(message), sig)
+}
+
+// VerifyTypedData 验证 EIP-712 签名，支持合约钱包
+func (v *Verifier) VerifyTypedData(ctx context.Context, signer common.Address, td *TypedData, sig []byte) (bool, error) {
+	hash, err := TypedDataHash(td)
+	if err != nil {
+		return false, err
+	}
+	return v.VerifyHash(ctx, signer, hash, sig)
+}
+
+// verifyCounterfactual 模拟部署钱包后再验证签名，不会产生链上交易
+func (v *Verifier) verifyCounterfactual(ctx context.Context, signer common.Address, hash common.Hash, wrapped *ERC6492Signature) (bool, error) {
+	sim, ok := v.backend.(simulator)
+	if !ok {
+		return false, ErrSimulateUnsupported
+	}
+	input, err := erc1271.Pack("isValidSignature", hash, wrapped.Signature)
+	if err != nil {
+		return false, err
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	results, err := sim.SimulateV1(ctx, ethclient.SimulateOptions{
+		BlockStateCalls: []ethclient.SimulateBlock{{
+			Calls: []ethereum.CallMsg{
+				{To: &wrapped.Factory, Data: wrapped.FactoryCalldata},
+				{To: &signer, Data: input},
+			},
+		}},
+	}, &latest)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSimulateUnsupported, err)
+	}
+	if len(results) != 1 || len(results[0].Calls) != 2 {
+		return false, errors.New("eth_simulateV1 返回结果数量不对")
+	}
+	deploy, check := results[0].Calls[0], results[0].Calls[1]
+	if deploy.Status != 1 {
+		return false, fmt.Errorf("模拟部署钱包失败: %s", callError(deploy))
+	}
+	return check.Status == 1 && isMagicValue(check.ReturnValue), nil
+}
+
+func callError(res ethclient.SimulateCallResult) string {
+	if res.Error != nil {
+		return res.Error.Message
+	}
+	return "execution reverted"
+}
@@ -0,0 +1,172 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"learn-web3-go/pkg/chain/model"
+	"learn-web3-go/pkg/deploy"
+)
+
+// walletEnv 模拟链: owner 部署了一个 MockWallet，链上有确定性部署代理用于 ERC-6492 的未部署钱包
+type walletEnv struct {
+	client   simulated.Client
+	owner    *model.User
+	stranger *model.User
+	wallet   common.Address // 已部署的合约钱包
+	verifier *Verifier
+}
+
+func newWalletEnv(t *testing.T) *walletEnv {
+	t.Helper()
+	env := &walletEnv{owner: newTestUser(t), stranger: newTestUser(t)}
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		env.owner.Address:   {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+		deploy.ProxyAddress: {Code: deploy.ProxyRuntime},
+	})
+	t.Cleanup(func() { backend.Close() })
+	env.client = backend.Client()
+
+	chainID, err := env.client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(env.owner.PrivateKey, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet, _, _, err := bind.DeployContract(auth, abi.ABI{}, mockWalletInitCode(env.owner.Address), env.client)
+	if err != nil {
+		t.Fatal("部署合约钱包失败: ", err)
+	}
+	backend.Commit()
+	env.wallet = wallet
+	env.verifier = NewVerifier(env.client)
+	return env
+}
+
+func newTestUser(t *testing.T) *model.User {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.User{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}
+}
+
+// sign 对消息做 personal_sign
+func (e *walletEnv) sign(t *testing.T, user *model.User, message []byte) []byte {
+	t.Helper()
+	sig, err := SignPersonalMessage(user, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestVerifierEOA(t *testing.T) {
+	env := newWalletEnv(t)
+	ctx := context.Background()
+	message := []byte("welcome to study web3 with golang")
+	sig := env.sign(t, env.owner, message)
+
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.owner.Address, message, sig); err != nil || !ok {
+		t.Fatalf("EOA 签名验证失败: %v", err)
+	}
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.stranger.Address, message, sig); err != nil || ok {
+		t.Fatalf("其它 EOA 不应该通过验证 (%v)", err)
+	}
+}
+
+func TestVerifierERC1271(t *testing.T) {
+	env := newWalletEnv(t)
+	ctx := context.Background()
+	message := []byte("welcome to study web3 with golang")
+	sig := env.sign(t, env.owner, message)
+
+	if deployed, err := IsContract(ctx, env.client, env.wallet); err != nil || !deployed {
+		t.Fatalf("钱包地址上没有代码 (%v)", err)
+	}
+	// 签名由 owner 给出，签名者是合约地址
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.wallet, message, sig); err != nil || !ok {
+		t.Fatalf("owner 的签名验证失败: %v", err)
+	}
+	if ok, err := IsValidSignature(ctx, env.client, env.wallet, PersonalMessageHash(message), sig); err != nil || !ok {
+		t.Fatalf("isValidSignature 应该返回 0x1626ba7e (%v)", err)
+	}
+	// 单纯的 ecrecover 会拒绝合约钱包的签名
+	if ok, _ := VerifyPersonalMessage(env.wallet, message, sig); ok {
+		t.Fatal("ecrecover 不应该接受合约钱包的签名")
+	}
+
+	bad := env.sign(t, env.stranger, message)
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.wallet, message, bad); err != nil || ok {
+		t.Fatalf("非 owner 的签名不应该通过验证 (%v)", err)
+	}
+	// 签名长度不对时 MockWallet 返回 0xffffffff
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.wallet, message, sig[:64]); err != nil || ok {
+		t.Fatalf("64 字节的签名不应该通过验证 (%v)", err)
+	}
+	// 消息被修改
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, env.wallet, append(message, '!'), sig); err != nil || ok {
+		t.Fatalf("修改后的消息不应该通过验证 (%v)", err)
+	}
+}
+
+func TestVerifierERC6492(t *testing.T) {
+	env := newWalletEnv(t)
+	ctx := context.Background()
+	message := []byte("welcome to study web3 with golang")
+
+	// 还没有部署的合约钱包，地址通过 CREATE2 提前计算
+	salt := common.BigToHash(big.NewInt(1))
+	initCode := mockWalletInitCode(env.owner.Address)
+	counterfactual := deploy.Create2Address(deploy.ProxyAddress, salt, initCode)
+	wrap := func(sig []byte) []byte {
+		t.Helper()
+		wrapped, err := (&ERC6492Signature{
+			Factory:         deploy.ProxyAddress,
+			FactoryCalldata: append(salt.Bytes(), initCode...),
+			Signature:       sig,
+		}).Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wrapped
+	}
+
+	sig := wrap(env.sign(t, env.owner, message))
+	if !IsERC6492Signature(sig) {
+		t.Fatal("包装后的签名应该以 ERC-6492 后缀结尾")
+	}
+	parsed, err := ParseERC6492Signature(sig)
+	if err != nil || parsed.Factory != deploy.ProxyAddress || !bytes.Equal(parsed.Signature, env.sign(t, env.owner, message)) {
+		t.Fatalf("解析 ERC-6492 签名失败: %v", err)
+	}
+
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, counterfactual, message, sig); err != nil || !ok {
+		t.Fatalf("未部署钱包的签名验证失败: %v", err)
+	}
+	if ok, err := env.verifier.VerifyPersonalMessage(ctx, counterfactual, message, wrap(env.sign(t, env.stranger, message))); err != nil || ok {
+		t.Fatalf("非 owner 的签名不应该通过验证 (%v)", err)
+	}
+	// 部署后地址不一致 (换了 salt)
+	other := deploy.Create2Address(deploy.ProxyAddress, common.BigToHash(big.NewInt(2)), initCode)
+	if ok, _ := env.verifier.VerifyPersonalMessage(ctx, other, message, sig); ok {
+		t.Fatal("签名者不是 factory 部署出的地址时不应该通过验证")
+	}
+
+	// 验证只是模拟执行，钱包仍然没有部署
+	if deployed, err := IsContract(ctx, env.client, counterfactual); err != nil || deployed {
+		t.Fatalf("验证后钱包不应该被部署 (%v)", err)
+	}
+}
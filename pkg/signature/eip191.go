@@ -0,0 +1,114 @@
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// EIP-191 签名数据的版本号
+const (
+	VersionIntendedValidator byte = 0x00 // 0x19 0x00 <validator 地址> <数据>
+	VersionStructuredData    byte = 0x01 // 0x19 0x01 <domainSeparator> <structHash> (EIP-712)
+	VersionPersonalSign      byte = 0x45 // 0x19 "Ethereum Signed Message:\n" <长度> <消息>
+)
+
+// ErrInvalidSignature 签名格式错误
+var ErrInvalidSignature = errors.New("签名格式有误")
+
+// PersonalMessageHash 计算 personal_sign 的哈希
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)，与 MetaMask、ethers 一致
+func PersonalMessageHash(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// IntendedValidatorHash 计算 EIP-191 版本 0x00 的哈希，数据只对指定的验证合约有效
+func IntendedValidatorHash(validator common.Address, data []byte) common.Hash {
+	return crypto.Keccak256Hash([]byte{0x19, VersionIntendedValidator}, validator.Bytes(), data)
+}
+
+// SignHash 对 32 字节哈希签名，返回 v = 27/28 的 65 字节签名
+func SignHash(hash common.Hash, user *model.User) ([]byte, error) {
+	sig, err := crypto.Sign(hash.Bytes(), user.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // crypto 库生成的是 0 或 1, 钱包通用的格式是 27 或 28
+	return sig, nil
+}
+
+// RecoverHash 从签名中恢复地址，v 可以是 0/1 也可以是 27/28
+// s 大于 secp256k1n/2 的签名会被拒绝 (EIP-2)，否则同一条消息可以构造出两个都有效的签名
+func RecoverHash(hash common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+	rsv := append([]byte{}, sig...)
+	switch rsv[64] {
+	case 27, 28:
+		rsv[64] -= 27
+	case 0, 1:
+	default:
+		return common.Address{}, fmt.Errorf("%w: 无效的 v 值 %d", ErrInvalidSignature, sig[64])
+	}
+	r, s := new(big.Int).SetBytes(rsv[:32]), new(big.Int).SetBytes(rsv[32:64])
+	if !crypto.ValidateSignatureValues(rsv[64], r, s, true) {
+		return common.Address{}, fmt.Errorf("%w: r 或 s 超出范围", ErrInvalidSignature)
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignPersonalMessage 按 personal_sign 的格式签名消息
+func SignPersonalMessage(user *model.User, message []byte) ([]byte, error) {
+	return SignHash(PersonalMessageHash(message), user)
+}
+
+// RecoverPersonalMessage 从 personal_sign 签名中恢复签名者地址
+func RecoverPersonalMessage(message, sig []byte) (common.Address, error) {
+	return RecoverHash(PersonalMessageHash(message), sig)
+}
+
+// VerifyPersonalMessage 验证 personal_sign 签名是否来自指定地址
+func VerifyPersonalMessage(address common.Address, message, sig []byte) (bool, error) {
+	signer, err := RecoverPersonalMessage(message, sig)
+	if err != nil {
+		return false, err
+	}
+	return signer == address, nil
+}
+
+// SignIntendedValidator 按 EIP-191 版本 0x00 签名
+func SignIntendedValidator(user *model.User, validator common.Address, data []byte) ([]byte, error) {
+	return SignHash(IntendedValidatorHash(validator, data), user)
+}
+
+// VerifyIntendedValidator 验证 EIP-191 版本 0x00 的签名
+func VerifyIntendedValidator(address, validator common.Address, data, sig []byte) (bool, error) {
+	signer, err := RecoverHash(IntendedValidatorHash(validator, data), sig)
+	if err != nil {
+		return false, err
+	}
+	return signer == address, nil
+}
+
+// DecodeSignature 解析 0x 开头的十六进制签名
+func DecodeSignature(s string) ([]byte, error) {
+	sig, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: 长度必须是 65 字节", ErrInvalidSignature)
+	}
+	return sig, nil
+}
@@ -0,0 +1,166 @@
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// 测试账户来自 ethers 文档的示例钱包:
+// new Wallet("0x0123456789012345678901234567890123456789012345678901234567890123").address
+var (
+	testKey, _  = crypto.HexToECDSA("0123456789012345678901234567890123456789012345678901234567890123")
+	testAddress = common.HexToAddress("0x14791697260E4c9A71f18484C9f997B308e59325")
+	testUser    = &model.User{Address: testAddress, PrivateKey: testKey}
+)
+
+// personalSignVectors 测试账户的 personal_sign 签名 (v = 27/28)
+// ethers 的 wallet.signMessage、MetaMask 的 personal_sign 和 go-ethereum 都使用 RFC 6979 确定性签名并取 low-s，
+// 同一个私钥和消息在三者中得到的签名完全相同
+var personalSignVectors = []struct {
+	message string
+	sig     string
+}{
+	{"Hello World", "0xe0ed34fbbe927a58267ce2e8067a611c69869e20e731bc99187a8bc97058664c16de07f7660f06ce0985d1d8e063726783033fda59b307897f26a21392d62b3a1c"},
+	{"Example `personal_sign` message", "0x289dbb64c9dd31f467fc3b907d31e09749c0d0dde2f81ec780800a06b5be85d92a2301ee7f6da02901cf13d5219bceb88d378e1c858600a15bea03a326f782db1b"},
+	{"welcome to study web3 with golang", "0xbfea285cb5ff2a579c1ebac66ee752426c53ab970ce40e5aed9fb9d30e99ddd12d84cab0400a34d9364af21e2342a4e6c50384637f45b7339d1f6d76ec1578fa1c"},
+	{"", "0xf93927710a3a451d0a5e5c00013078156e6e1de125513ef855322bb7cd82f8464973d0cf7adc5809004833afc97f5eb98ab3ed5a8a5ad38b2d4e10e071b36faf1b"},
+}
+
+func TestPersonalMessageHash(t *testing.T) {
+	// 与 go-ethereum accounts.TextHash 的测试向量一致
+	want := common.HexToHash("0xa080337ae51c4e064c189e113edd0ba391df9206e2f49db658bb32cf2911730b")
+	if got := PersonalMessageHash([]byte("Hello Joe")); got != want {
+		t.Fatalf("哈希 %s, 期望 %s", got.Hex(), want.Hex())
+	}
+	// 长度按十进制字符串拼接，前缀中没有空格
+	raw := append([]byte("\x19Ethereum Signed Message:\n11"), "Hello World"...)
+	if got := PersonalMessageHash([]byte("Hello World")); got != crypto.Keccak256Hash(raw) {
+		t.Fatalf("前缀格式错误: %s", got.Hex())
+	}
+}
+
+func TestSignPersonalMessage(t *testing.T) {
+	for _, tc := range personalSignVectors {
+		sig, err := SignPersonalMessage(testUser, []byte(tc.message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hexutil.Encode(sig); got != tc.sig {
+			t.Errorf("%q: 签名 %s, 期望 %s", tc.message, got, tc.sig)
+		}
+	}
+}
+
+func TestVerifyPersonalMessage(t *testing.T) {
+	for _, tc := range personalSignVectors {
+		sig, err := DecodeSignature(tc.sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 钱包返回 v = 27/28，部分硬件钱包和库返回 v = 0/1，两种都要接受
+		legacy := append([]byte{}, sig...)
+		legacy[64] -= 27
+		for _, s := range [][]byte{sig, legacy} {
+			ok, err := VerifyPersonalMessage(testAddress, []byte(tc.message), s)
+			if err != nil || !ok {
+				t.Errorf("%q v=%d: 验证失败 %v", tc.message, s[64], err)
+			}
+		}
+
+		// 消息被修改后恢复出的是另一个地址
+		ok, err := VerifyPersonalMessage(testAddress, []byte(tc.message+"!"), sig)
+		if err != nil || ok {
+			t.Errorf("%q: 修改后的消息不应该通过验证 (%v)", tc.message, err)
+		}
+	}
+}
+
+func TestIntendedValidator(t *testing.T) {
+	validator := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	data := []byte("withdraw 100")
+
+	// keccak256(0x19 0x00 <validator> <data>)
+	want := common.HexToHash("0x53cf645e59649f6c55d01a0c89f3fd579f32d60a7a7135d286ef1e6be5a206c3")
+	if got := IntendedValidatorHash(validator, data); got != want {
+		t.Fatalf("哈希 %s, 期望 %s", got.Hex(), want.Hex())
+	}
+
+	sig, err := SignIntendedValidator(testUser, validator, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSig := "0x6ce211e605236fd82141d25bc6fe09e76a9198783fe007fa20f46b6c5de74b363aba225effc9c3fdb73be3583785eac935161b3d232e4eb98fa672fba875f31d1b"
+	if got := hexutil.Encode(sig); got != wantSig {
+		t.Fatalf("签名 %s, 期望 %s", got, wantSig)
+	}
+	if ok, err := VerifyIntendedValidator(testAddress, validator, data, sig); err != nil || !ok {
+		t.Fatalf("验证失败 %v", err)
+	}
+
+	// 签名只对指定的验证合约有效，也不能当作 personal_sign 签名使用
+	other := common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+	if ok, _ := VerifyIntendedValidator(testAddress, other, data, sig); ok {
+		t.Fatal("换了验证合约后不应该通过验证")
+	}
+	if ok, _ := VerifyPersonalMessage(testAddress, data, sig); ok {
+		t.Fatal("版本 0x00 的签名不应该通过 personal_sign 验证")
+	}
+}
+
+func TestRecoverHashRejects(t *testing.T) {
+	hash := PersonalMessageHash([]byte(personalSignVectors[0].message))
+	valid := hexutil.MustDecode(personalSignVectors[0].sig)
+
+	// s' = n - s 并翻转 v，数学上能恢复出同一个公钥，但不符合 EIP-2
+	highS := append([]byte{}, valid...)
+	s := new(big.Int).SetBytes(valid[32:64])
+	copy(highS[32:64], common.LeftPadBytes(new(big.Int).Sub(crypto.S256().Params().N, s).Bytes(), 32))
+	highS[64] ^= 1
+
+	withV := func(v byte) []byte {
+		sig := append([]byte{}, valid...)
+		sig[64] = v
+		return sig
+	}
+
+	tests := []struct {
+		name string
+		sig  []byte
+	}{
+		{"空签名", nil},
+		{"64 字节", valid[:64]},
+		{"66 字节", append(append([]byte{}, valid...), 0)},
+		{"v=2", withV(2)},
+		{"v=26", withV(26)},
+		{"v=29", withV(29)},
+		{"v=37 (EIP-155)", withV(37)},
+		{"high-s", highS},
+		{"r=0", append(make([]byte, 32), valid[32:]...)},
+	}
+	for _, tc := range tests {
+		if _, err := RecoverHash(hash, tc.sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: 错误 %v, 期望 ErrInvalidSignature", tc.name, err)
+		}
+	}
+
+	if _, err := DecodeSignature("0x" + hexutil.Encode(valid)[2:130]); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("DecodeSignature 64 字节: 错误 %v, 期望 ErrInvalidSignature", err)
+	}
+	if _, err := DecodeSignature("not hex"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("DecodeSignature 非十六进制: 错误 %v, 期望 ErrInvalidSignature", err)
+	}
+	// 原签名仍然有效
+	if signer, err := RecoverHash(hash, valid); err != nil || signer != testAddress {
+		t.Fatalf("恢复 %s (%v), 期望 %s", signer.Hex(), err, testAddress.Hex())
+	}
+	if !bytes.Equal(valid, hexutil.MustDecode(personalSignVectors[0].sig)) {
+		t.Fatal("RecoverHash 修改了传入的签名")
+	}
+}
@@ -0,0 +1,82 @@
+package signature
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"learn-web3-go/pkg/chain/model"
+)
+
+// TypedData EIP-712 结构化数据，格式与钱包 eth_signTypedData_v4 的 JSON 一致
+type TypedData = apitypes.TypedData
+
+// ParseTypedData 解析 eth_signTypedData_v4 的 JSON 数据
+func ParseTypedData(data []byte) (*TypedData, error) {
+	td := new(TypedData)
+	if err := json.Unmarshal(data, td); err != nil {
+		return nil, fmt.Errorf("typed data 格式有误: %w", err)
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return nil, fmt.Errorf("types 中缺少 primaryType: %s", td.PrimaryType)
+	}
+	if _, ok := td.Types["EIP712Domain"]; !ok {
+		return nil, fmt.Errorf("types 中缺少 EIP712Domain")
+	}
+	return td, nil
+}
+
+// DomainSeparator 计算域分隔符 hashStruct(EIP712Domain)
+func DomainSeparator(td *TypedData) (common.Hash, error) {
+	hash, err := td.HashStruct("EIP712Domain", td.Domain.Map())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// StructHash 计算消息的结构体哈希 hashStruct(primaryType, message)，支持嵌套结构体和数组
+func StructHash(td *TypedData) (common.Hash, error) {
+	hash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// TypedDataHash 计算需要签名的哈希 keccak256(0x19 0x01 domainSeparator structHash)
+func TypedDataHash(td *TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(*td)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// SignTypedData 使用用户私钥对结构化数据签名
+func SignTypedData(user *model.User, td *TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(td)
+	if err != nil {
+		return nil, err
+	}
+	return SignHash(hash, user)
+}
+
+// RecoverTypedData 从签名中恢复签名者地址
+func RecoverTypedData(td *TypedData, sig []byte) (common.Address, error) {
+	hash, err := TypedDataHash(td)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverHash(hash, sig)
+}
+
+// VerifyTypedData 验证结构化数据的签名是否来自指定地址
+func VerifyTypedData(address common.Address, td *TypedData, sig []byte) (bool, error) {
+	signer, err := RecoverTypedData(td, sig)
+	if err != nil {
+		return false, err
+	}
+	return signer == address, nil
+}
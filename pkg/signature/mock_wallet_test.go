@@ -0,0 +1,48 @@
+package signature
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 模拟的 EIP-1271 合约钱包，用于在模拟链上测试，等价于下面的 Solidity:
+//
+//	contract MockWallet {
+//	    address immutable owner;
+//	    function isValidSignature(bytes32 hash, bytes calldata sig) external view returns (bytes4) {
+//	        return ecrecover(hash, v, r, s) == owner ? 0x1626ba7e : 0xffffffff;
+//	    }
+//	}
+//
+// 环境中没有 solc，这里直接手写字节码，owner 地址通过 PUSH20 写入运行时代码
+
+// mockWalletRuntime 生成运行时字节码 (142 字节)
+func mockWalletRuntime(owner common.Address) []byte {
+	code := common.FromHex(
+		// 检查函数选择器 0x1626ba7e，不匹配则 revert
+		"600035" + "60e01c" + "631626ba7e" + "14" + "601357" + "600080fd" +
+			// 0x13: hash 写入 mem[0x00]，p = 4 + 签名的偏移量
+			"5b" + "600435" + "600052" + "602435" + "600401" +
+			// 签名长度必须是 65，否则跳转到 0x7d
+			"80" + "35" + "6041" + "14" + "15" + "607d57" +
+			// r -> mem[0x40], s -> mem[0x60], v -> mem[0x20]
+			"80602001356040" + "52" + "80604001356060" + "52" + "6060013560001a602052" +
+			// staticcall(gas, 0x01 ecrecover, 0x00, 0x80, 0x80, 0x20)
+			"6020608060806000" + "6001" + "5a" + "fa" + "50" +
+			// mem[0x80] == owner ?
+			"608051" + "73" + owner.Hex()[2:] + "14" + "15" + "607d57" +
+			// 返回 0x1626ba7e
+			"631626ba7e" + "60e01b" + "600052" + "60206000f3" +
+			// 0x7d: 返回 0xffffffff
+			"5b" + "63ffffffff" + "60e01b" + "600052" + "60206000f3",
+	)
+	return code
+}
+
+// mockWalletInitCode 部署用的字节码: 把运行时代码拷贝到内存后返回
+func mockWalletInitCode(owner common.Address) []byte {
+	runtime := mockWalletRuntime(owner)
+	size := byte(len(runtime))
+	// PUSH1 size PUSH1 0x0c PUSH1 0 CODECOPY PUSH1 size PUSH1 0 RETURN
+	init := []byte{0x60, size, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, size, 0x60, 0x00, 0xf3}
+	return append(init, runtime...)
+}
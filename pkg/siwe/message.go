@@ -0,0 +1,289 @@
+package siwe
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/pkg/signature"
+)
+
+// Sign-In with Ethereum (EIP-4361) 登录消息
+
+const (
+	headerSuffix = " wants you to sign in with your Ethereum account:"
+	uriTag       = "URI: "
+	versionTag   = "Version: "
+	chainTag     = "Chain ID: "
+	nonceTag     = "Nonce: "
+	issuedAtTag  = "Issued At: "
+	expTag       = "Expiration Time: "
+	notBeforeTag = "Not Before: "
+	requestIDTag = "Request ID: "
+	resourcesTag = "Resources:"
+)
+
+var nonceRe = regexp.MustCompile("^[a-zA-Z0-9]{8,}$")
+
+// 校验失败的原因
+var (
+	ErrInvalidMessage   = errors.New("SIWE 消息格式有误")
+	ErrDomainMismatch   = errors.New("SIWE 域名不匹配")
+	ErrURIMismatch      = errors.New("SIWE URI 不匹配")
+	ErrChainMismatch    = errors.New("SIWE 链 ID 不匹配")
+	ErrNonceMismatch    = errors.New("SIWE nonce 不匹配")
+	ErrExpired          = errors.New("SIWE 消息已经过期")
+	ErrNotYetValid      = errors.New("SIWE 消息还没有生效")
+	ErrInvalidSignature = errors.New("SIWE 签名无效")
+)
+
+// Message EIP-4361 登录消息
+type Message struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+
+	raw string // 解析时的原文，验证签名时必须使用原文 (时间格式等可能与 String 的结果不同)
+}
+
+// VerifyOptions 服务端期望的消息内容
+type VerifyOptions struct {
+	Domain  string
+	URI     string // 为空时不检查
+	ChainID uint64
+	Nonce   string
+	Now     time.Time // 为零值时使用当前时间
+
+	// Verifier 不为空时支持合约钱包 (EIP-1271 / ERC-6492) 的签名，为空时只支持 EOA
+	Verifier *signature.Verifier
+}
+
+// NewNonce 生成一个随机 nonce (16 位字母数字)
+func NewNonce() (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b), nil
+}
+
+// String 按 EIP-4361 格式生成需要钱包签名的文本
+func (m *Message) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Domain + headerSuffix + "\n")
+	sb.WriteString(m.Address.Hex() + "\n")
+	sb.WriteString("\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement + "\n")
+		sb.WriteString("\n")
+	}
+	sb.WriteString(uriTag + m.URI + "\n")
+	sb.WriteString(versionTag + m.Version + "\n")
+	sb.WriteString(chainTag + strconv.FormatUint(m.ChainID, 10) + "\n")
+	sb.WriteString(nonceTag + m.Nonce + "\n")
+	sb.WriteString(issuedAtTag + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		sb.WriteString("\n" + expTag + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		sb.WriteString("\n" + notBeforeTag + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		sb.WriteString("\n" + requestIDTag + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\n" + resourcesTag)
+		for _, r := range m.Resources {
+			sb.WriteString("\n- " + r)
+		}
+	}
+	return sb.String()
+}
+
+// ParseMessage 解析钱包签名的 SIWE 文本
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 8 {
+		return nil, ErrInvalidMessage
+	}
+	m := &Message{raw: text}
+
+	// 第 1 行: <domain> wants you to sign in with your Ethereum account:
+	if !strings.HasSuffix(lines[0], headerSuffix) {
+		return nil, fmt.Errorf("%w: 缺少标题行", ErrInvalidMessage)
+	}
+	m.Domain = strings.TrimSuffix(lines[0], headerSuffix)
+	if m.Domain == "" {
+		return nil, fmt.Errorf("%w: 缺少域名", ErrInvalidMessage)
+	}
+
+	// 第 2 行: EIP-55 校验和格式的地址
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("%w: 地址无效", ErrInvalidMessage)
+	}
+	m.Address = common.HexToAddress(lines[1])
+	if m.Address.Hex() != lines[1] {
+		return nil, fmt.Errorf("%w: 地址必须是 EIP-55 校验和格式", ErrInvalidMessage)
+	}
+
+	// 第 3 行为空行，之后是可选的 statement 和一个空行
+	if lines[2] != "" {
+		return nil, fmt.Errorf("%w: 地址后缺少空行", ErrInvalidMessage)
+	}
+	i := 3
+	if !strings.HasPrefix(lines[i], uriTag) {
+		m.Statement = lines[i]
+		if i+1 >= len(lines) || lines[i+1] != "" {
+			return nil, fmt.Errorf("%w: statement 后缺少空行", ErrInvalidMessage)
+		}
+		i += 2
+	}
+
+	// 必填字段，顺序固定
+	var err error
+	next := func(tag string) (string, error) {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], tag) {
+			return "", fmt.Errorf("%w: 缺少 %s", ErrInvalidMessage, strings.TrimSpace(tag))
+		}
+		value := strings.TrimPrefix(lines[i], tag)
+		i++
+		return value, nil
+	}
+	if m.URI, err = next(uriTag); err != nil {
+		return nil, err
+	}
+	if m.Version, err = next(versionTag); err != nil {
+		return nil, err
+	}
+	if m.Version != "1" {
+		return nil, fmt.Errorf("%w: 不支持的版本 %s", ErrInvalidMessage, m.Version)
+	}
+	chainID, err := next(chainTag)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseUint(chainID, 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: 链 ID 无效", ErrInvalidMessage)
+	}
+	if m.Nonce, err = next(nonceTag); err != nil {
+		return nil, err
+	}
+	if !nonceRe.MatchString(m.Nonce) {
+		return nil, fmt.Errorf("%w: nonce 至少 8 位字母数字", ErrInvalidMessage)
+	}
+	issuedAt, err := next(issuedAtTag)
+	if err != nil {
+		return nil, err
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339, issuedAt); err != nil {
+		return nil, fmt.Errorf("%w: Issued At 时间格式有误", ErrInvalidMessage)
+	}
+
+	// 可选字段，顺序固定
+	if i < len(lines) && strings.HasPrefix(lines[i], expTag) {
+		t, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[i], expTag))
+		if err != nil {
+			return nil, fmt.Errorf("%w: Expiration Time 时间格式有误", ErrInvalidMessage)
+		}
+		m.ExpirationTime = &t
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], notBeforeTag) {
+		t, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[i], notBeforeTag))
+		if err != nil {
+			return nil, fmt.Errorf("%w: Not Before 时间格式有误", ErrInvalidMessage)
+		}
+		m.NotBefore = &t
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], requestIDTag) {
+		m.RequestID = strings.TrimPrefix(lines[i], requestIDTag)
+		i++
+	}
+	if i < len(lines) && lines[i] == resourcesTag {
+		i++
+		for ; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], "- "))
+		}
+	}
+	if i != len(lines) {
+		return nil, fmt.Errorf("%w: 第 %d 行无法识别", ErrInvalidMessage, i+1)
+	}
+	return m, nil
+}
+
+// Validate 检查消息内容是否符合服务端的期望 (不检查签名)
+func (m *Message) Validate(opts VerifyOptions) error {
+	if m.Domain != opts.Domain {
+		return ErrDomainMismatch
+	}
+	if opts.URI != "" && m.URI != opts.URI {
+		return ErrURIMismatch
+	}
+	if m.ChainID != opts.ChainID {
+		return ErrChainMismatch
+	}
+	if m.Nonce != opts.Nonce {
+		return ErrNonceMismatch
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return ErrExpired
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return ErrNotYetValid
+	}
+	// 签发时间不能在未来 (允许 1 分钟的时钟误差)
+	if m.IssuedAt.After(now.Add(time.Minute)) {
+		return ErrNotYetValid
+	}
+	return nil
+}
+
+// Verify 检查消息内容，并验证 personal_sign 签名来自消息中的地址
+func (m *Message) Verify(ctx context.Context, sig []byte, opts VerifyOptions) error {
+	if err := m.Validate(opts); err != nil {
+		return err
+	}
+	text := m.raw
+	if text == "" {
+		text = m.String()
+	}
+
+	var (
+		ok  bool
+		err error
+	)
+	if opts.Verifier != nil {
+		ok, err = opts.Verifier.VerifyPersonalMessage(ctx, m.Address, []byte(text), sig)
+	} else {
+		ok, err = signature.VerifyPersonalMessage(m.Address, []byte(text), sig)
+	}
+	if err != nil || !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
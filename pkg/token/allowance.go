@@ -0,0 +1,130 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"learn-web3-go/contracts/erc20"
+)
+
+// approveGasLimit 前一笔 approve 还没有上链时无法估算 gas，这里使用固定值
+const approveGasLimit = 80000
+
+// Revoke 取消对 spender 的授权
+func Revoke(auth *bind.TransactOpts, token *erc20.ERC20, spender common.Address) (*types.Transaction, error) {
+	return token.Approve(auth, spender, new(big.Int))
+}
+
+// EnsureAllowance 保证 spender 的额度至少为 amount，已经足够时不发交易
+//   - 当前额度为 0: 直接 approve
+//   - 否则优先使用 increaseAllowance (OpenZeppelin 扩展，避免 approve 的抢跑问题)
+//   - 代币不支持 increaseAllowance 时先 approve(0) 再 approve(amount)，USDT 这类代币要求额度不为 0 时不能直接修改
+//
+// 返回按顺序发出的交易，调用方需要等待最后一笔上链
+func EnsureAllowance(auth *bind.TransactOpts, backend bind.ContractBackend, tokenAddr, spender common.Address, amount *big.Int) ([]*types.Transaction, error) {
+	ctx := auth.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	token, err := erc20.NewERC20(tokenAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	current, err := token.Allowance(&bind.CallOpts{Context: ctx}, auth.From, spender)
+	if err != nil {
+		return nil, err
+	}
+	if current.Cmp(amount) >= 0 {
+		return nil, nil
+	}
+	if current.Sign() == 0 {
+		tx, err := token.Approve(auth, spender, amount)
+		if err != nil {
+			return nil, err
+		}
+		return []*types.Transaction{tx}, nil
+	}
+
+	// 先用 eth_call 探测代币是否支持 increaseAllowance，只有合约执行失败时才改用两次 approve，其它错误直接返回
+	// 预设了 GasLimit 时发送不会估算 gas，不探测的话会广播一笔注定失败的交易
+	added := new(big.Int).Sub(amount, current)
+	supported, err := supportsIncreaseAllowance(ctx, backend, tokenAddr, auth.From, spender, added)
+	if err != nil {
+		return nil, err
+	}
+	if supported {
+		tx, err := token.IncreaseAllowance(auth, spender, added)
+		if err != nil {
+			return nil, err
+		}
+		return []*types.Transaction{tx}, nil
+	}
+
+	nonce, err := backend.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		return nil, err
+	}
+	resetOpts := *auth
+	resetOpts.Nonce = new(big.Int).SetUint64(nonce)
+	reset, err := token.Approve(&resetOpts, spender, new(big.Int))
+	if err != nil {
+		return nil, fmt.Errorf("额度清零失败: %w", err)
+	}
+	approveOpts := *auth
+	approveOpts.Nonce = new(big.Int).SetUint64(nonce + 1)
+	if approveOpts.GasLimit == 0 {
+		approveOpts.GasLimit = approveGasLimit
+	}
+	tx, err := token.Approve(&approveOpts, spender, amount)
+	if err != nil {
+		return []*types.Transaction{reset}, fmt.Errorf("设置额度失败: %w", err)
+	}
+	return []*types.Transaction{reset, tx}, nil
+}
+
+// supportsIncreaseAllowance 用 eth_call 模拟 increaseAllowance
+// revert 或返回值不是 true (例如 fallback 函数吞掉了调用) 视为不支持，网络或节点错误原样返回
+func supportsIncreaseAllowance(ctx context.Context, backend bind.ContractCaller, token, from, spender common.Address, added *big.Int) (bool, error) {
+	data, err := erc20ABI.Pack("increaseAllowance", spender, added)
+	if err != nil {
+		return false, err
+	}
+	out, err := backend.CallContract(ctx, ethereum.CallMsg{From: from, To: &token, Data: data}, nil)
+	if err != nil {
+		if isExecutionError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(out) >= 32 && new(big.Int).SetBytes(out[:32]).Sign() != 0, nil
+}
+
+// isExecutionError 判断 eth_call 的错误是否来自合约执行 (revert、无效指令)，而不是网络或节点错误
+func isExecutionError(err error) bool {
+	var revert rpc.DataError
+	if errors.As(err, &revert) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "execution reverted") || strings.Contains(msg, "invalid opcode") || strings.Contains(msg, "invalid jump")
+}
+
+// TransferFrom spender (auth.From) 使用额度把代币从 from 转给 to，发送前检查额度是否足够
+func TransferFrom(auth *bind.TransactOpts, token *erc20.ERC20, from, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	allowance, err := token.Allowance(&bind.CallOpts{Context: auth.Context}, from, auth.From)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("额度不足: 需要 %s, 当前 %s", amount, allowance)
+	}
+	return token.TransferFrom(auth, from, to, amount)
+}
@@ -0,0 +1,167 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Amount 代币数量: 最小单位的整数 + 精度，例如 1.5 USDT = {1500000, 6}
+// 解析、格式化和运算都不经过浮点数，零值表示 0 (精度 0)
+type Amount struct {
+	value    *big.Int
+	decimals uint8
+}
+
+// NewAmount 由最小单位的数量创建 Amount，value 会被复制
+func NewAmount(value *big.Int, decimals uint8) Amount {
+	a := Amount{value: new(big.Int), decimals: decimals}
+	if value != nil {
+		a.value.Set(value)
+	}
+	return a
+}
+
+// ParseAmount 按精度解析十进制字符串，小数位超过精度时返回错误
+func ParseAmount(s string, decimals uint8) (Amount, error) {
+	v, err := ParseUnits(s, decimals)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{value: v, decimals: decimals}, nil
+}
+
+// ParseDecimal 解析精度未知的十进制字符串，精度取小数位数，例如 "1.50" -> {150, 2}
+// 用于 JSON 等无法提前知道代币精度的场景，使用前通过 Rescale 转换为代币的精度
+func ParseDecimal(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	_, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 255 {
+		return Amount{}, fmt.Errorf("数量 %q 的小数位过多", s)
+	}
+	return ParseAmount(s, uint8(len(frac)))
+}
+
+// Int 返回最小单位的数量 (副本)，用于传给合约
+func (a Amount) Int() *big.Int {
+	return new(big.Int).Set(a.int())
+}
+
+// Decimals 返回精度
+func (a Amount) Decimals() uint8 {
+	return a.decimals
+}
+
+// String 返回十进制字符串，去掉小数末尾的 0，例如 "1.5"
+func (a Amount) String() string {
+	return FormatUnits(a.int(), a.decimals)
+}
+
+// Sign 返回 -1, 0, 1
+func (a Amount) Sign() int {
+	return a.int().Sign()
+}
+
+// IsZero 是否为 0
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Rescale 转换为另一个精度，会丢失小数位时返回错误
+func (a Amount) Rescale(decimals uint8) (Amount, error) {
+	v := a.int()
+	switch {
+	case decimals > a.decimals:
+		v = new(big.Int).Mul(v, pow10(decimals-a.decimals))
+	case decimals < a.decimals:
+		q, r := new(big.Int).QuoRem(v, pow10(a.decimals-decimals), new(big.Int))
+		if r.Sign() != 0 {
+			return Amount{}, fmt.Errorf("数量 %s 的小数位超过代币精度 %d", a, decimals)
+		}
+		v = q
+	default:
+		v = new(big.Int).Set(v)
+	}
+	return Amount{value: v, decimals: decimals}, nil
+}
+
+// Add 返回 a + b，精度不同时使用较大的精度，不会丢失精度
+func (a Amount) Add(b Amount) Amount {
+	x, y := align(a, b)
+	return Amount{value: new(big.Int).Add(x.int(), y.int()), decimals: x.decimals}
+}
+
+// Sub 返回 a - b，精度不同时使用较大的精度
+func (a Amount) Sub(b Amount) Amount {
+	x, y := align(a, b)
+	return Amount{value: new(big.Int).Sub(x.int(), y.int()), decimals: x.decimals}
+}
+
+// Cmp 比较大小，a < b 返回 -1，相等返回 0，a > b 返回 1，精度不同时按数值比较
+func (a Amount) Cmp(b Amount) int {
+	x, y := align(a, b)
+	return x.int().Cmp(y.int())
+}
+
+// MarshalJSON 序列化为字符串，避免 JSON 数字在其它语言中被当成浮点数
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON 接受字符串 "1.5"，也兼容数字 1.5 (按原始文本解析，不经过 float64)
+// 解析后的精度是小数位数，需要通过 Rescale 转换为代币的精度
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s := string(data)
+	if bytes.HasPrefix(data, []byte{'"'}) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalText 用于 flag.TextVar 等文本场景
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText 同 UnmarshalJSON，精度是小数位数
+func (a *Amount) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func (a Amount) int() *big.Int {
+	if a.value == nil {
+		return new(big.Int)
+	}
+	return a.value
+}
+
+// align 把两个数量转换为相同的精度，放大不会丢失精度
+func align(a, b Amount) (Amount, Amount) {
+	if a.decimals < b.decimals {
+		a, _ = a.Rescale(b.decimals)
+	} else if b.decimals < a.decimals {
+		b, _ = b.Rescale(a.decimals)
+	}
+	return a, b
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
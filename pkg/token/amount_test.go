@@ -0,0 +1,271 @@
+package token
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func mustInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("无效的整数 %q", s)
+	}
+	return v
+}
+
+// unitsVectors 最小单位和十进制字符串互相转换，text 是 FormatUnits 的输出 (小数末尾没有 0)
+var unitsVectors = []struct {
+	raw      string
+	decimals uint8
+	text     string
+}{
+	{"0", 0, "0"},
+	{"1", 0, "1"},
+	{"123456789", 0, "123456789"},
+	{"-42", 0, "-42"},
+	{"0", 6, "0"},
+	{"1", 6, "0.000001"},
+	{"1500000", 6, "1.5"},
+	{"1000000", 6, "1"},
+	{"123456789012", 6, "123456.789012"},
+	{"-1500000", 6, "-1.5"},
+	{"-1", 6, "-0.000001"},
+	{"0", 18, "0"},
+	{"1", 18, "0.000000000000000001"},
+	{"1000000000000000000", 18, "1"},
+	{"1234500000000000000", 18, "1.2345"},
+	{"115792089237316195423570985008687907853269984665640564039457584007913129639935", 18, "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+	{"-2500000000000000000", 18, "-2.5"},
+}
+
+func TestFormatParseUnits(t *testing.T) {
+	for _, tc := range unitsVectors {
+		raw := mustInt(t, tc.raw)
+		if got := FormatUnits(raw, tc.decimals); got != tc.text {
+			t.Errorf("FormatUnits(%s, %d) = %q, 期望 %q", tc.raw, tc.decimals, got, tc.text)
+		}
+		v, err := ParseUnits(tc.text, tc.decimals)
+		if err != nil {
+			t.Errorf("ParseUnits(%q, %d): %v", tc.text, tc.decimals, err)
+			continue
+		}
+		if v.Cmp(raw) != 0 {
+			t.Errorf("ParseUnits(%q, %d) = %s, 期望 %s", tc.text, tc.decimals, v, tc.raw)
+		}
+
+		// Amount 的 String/Parse 与 FormatUnits/ParseUnits 一致
+		a := NewAmount(raw, tc.decimals)
+		if a.String() != tc.text {
+			t.Errorf("Amount(%s, %d).String() = %q, 期望 %q", tc.raw, tc.decimals, a.String(), tc.text)
+		}
+		parsed, err := ParseAmount(a.String(), tc.decimals)
+		if err != nil || parsed.Cmp(a) != 0 || parsed.Decimals() != tc.decimals {
+			t.Errorf("ParseAmount(%q, %d) = %v (%v), 期望 %v", a.String(), tc.decimals, parsed, err, a)
+		}
+	}
+	if got := FormatUnits(nil, 18); got != "0" {
+		t.Errorf("FormatUnits(nil) = %q", got)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint8
+		want     string // 为空表示应该返回错误
+	}{
+		{"1.5", 6, "1500000"},
+		{" 1.5 ", 6, "1500000"},
+		{"+1.5", 6, "1500000"},
+		{"-1.5", 6, "-1500000"},
+		{".5", 6, "500000"},
+		{"5.", 6, "5000000"},
+		{"007", 0, "7"},
+		{"-0", 6, "0"},
+		// 超出精度但末尾都是 0 时不算丢失精度
+		{"1.5000000000", 6, "1500000"},
+		{"0.000001", 6, "1"},
+		{"0.000000000000000001", 18, "1"},
+		// 小数位超过精度
+		{"0.0000001", 6, ""},
+		{"1.1234567", 6, ""},
+		{"0.5", 0, ""},
+		{"0.0000000000000000001", 18, ""},
+		{"-0.0000001", 6, ""},
+		// 格式错误
+		{"", 6, ""},
+		{"-", 6, ""},
+		{".", 6, ""},
+		{"1.2.3", 6, ""},
+		{"1e18", 18, ""},
+		{"0x10", 0, ""},
+		{"1,000", 6, ""},
+		{"--1", 6, ""},
+		{"abc", 6, ""},
+	}
+	for _, tc := range tests {
+		v, err := ParseUnits(tc.in, tc.decimals)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("ParseUnits(%q, %d) = %s, 应该返回错误", tc.in, tc.decimals, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseUnits(%q, %d): %v", tc.in, tc.decimals, err)
+			continue
+		}
+		if v.String() != tc.want {
+			t.Errorf("ParseUnits(%q, %d) = %s, 期望 %s", tc.in, tc.decimals, v, tc.want)
+		}
+	}
+}
+
+func TestAmountRescale(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to uint8
+		want     string // 为空表示会丢失精度，应该返回错误
+	}{
+		{"1.5", 6, 18, "1500000000000000000"},
+		{"1.5", 18, 6, "1500000"},
+		{"-1.5", 18, 6, "-1500000"},
+		{"1.5", 6, 6, "1500000"},
+		{"0", 18, 0, "0"},
+		{"100", 18, 0, "100"},
+		{"1.5", 1, 0, ""},
+		{"0.0000001", 18, 6, ""},
+		{"-0.0000001", 18, 6, ""},
+		{"1.000000000000000001", 18, 6, ""},
+	}
+	for _, tc := range tests {
+		a, err := ParseAmount(tc.in, tc.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := a.Rescale(tc.to)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("%s (%d) -> %d 位精度 = %s, 应该返回错误", tc.in, tc.from, tc.to, got.Int())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s (%d) -> %d 位精度: %v", tc.in, tc.from, tc.to, err)
+			continue
+		}
+		if got.Int().String() != tc.want || got.Decimals() != tc.to {
+			t.Errorf("%s (%d) -> %d 位精度 = {%s, %d}, 期望 {%s, %d}", tc.in, tc.from, tc.to, got.Int(), got.Decimals(), tc.want, tc.to)
+		}
+		// 原值不受影响
+		if want, _ := ParseAmount(tc.in, tc.from); a.Decimals() != tc.from || a.Int().Cmp(want.Int()) != 0 {
+			t.Errorf("Rescale 修改了原值: %v", a)
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	usdt := func(s string) Amount {
+		a, err := ParseAmount(s, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	eth := func(s string) Amount {
+		a, err := ParseAmount(s, 18)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	// 不同精度相加时使用较大的精度
+	sum := usdt("1.5").Add(eth("0.000000000000000001"))
+	if sum.Decimals() != 18 || sum.String() != "1.500000000000000001" {
+		t.Errorf("1.5 + 1e-18 = %s (%d)", sum, sum.Decimals())
+	}
+	if diff := usdt("1").Sub(usdt("2.5")); diff.String() != "-1.5" || diff.Sign() != -1 {
+		t.Errorf("1 - 2.5 = %s", diff)
+	}
+	if usdt("1.5").Cmp(eth("1.5")) != 0 || usdt("1.5").Cmp(eth("1.500000000000000001")) != -1 || eth("2").Cmp(usdt("1.999999")) != 1 {
+		t.Error("不同精度按数值比较的结果错误")
+	}
+	var zero Amount
+	if !zero.IsZero() || zero.String() != "0" || zero.Int().Sign() != 0 {
+		t.Errorf("零值 Amount = %s", zero)
+	}
+	// Int 返回副本
+	a := usdt("1")
+	a.Int().SetInt64(0)
+	if a.String() != "1" {
+		t.Errorf("修改 Int() 的返回值影响了 Amount: %s", a)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		decimals uint8
+		err      bool
+	}{
+		{`"1.5"`, "1.5", 1, false},
+		{`1.5`, "1.5", 1, false},
+		{`"1.50"`, "1.5", 2, false},
+		{`1.50`, "1.5", 2, false},
+		{`"-0.000001"`, "-0.000001", 6, false},
+		{`-0.000001`, "-0.000001", 6, false},
+		{`100`, "100", 0, false},
+		// 超过 float64 精度的数字按原始文本解析，不会变成 0.30000000000000004 之类的值
+		{`123456789.123456789123456789`, "123456789.123456789123456789", 18, false},
+		{`"123456789.123456789123456789"`, "123456789.123456789123456789", 18, false},
+		{`1e18`, "", 0, true},
+		{`"abc"`, "", 0, true},
+		{`true`, "", 0, true},
+	}
+	for _, tc := range tests {
+		var a Amount
+		err := json.Unmarshal([]byte(tc.in), &a)
+		if tc.err {
+			if err == nil {
+				t.Errorf("解析 %s = %s, 应该返回错误", tc.in, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("解析 %s: %v", tc.in, err)
+			continue
+		}
+		if a.String() != tc.want || a.Decimals() != tc.decimals {
+			t.Errorf("解析 %s = %s (%d), 期望 %s (%d)", tc.in, a, a.Decimals(), tc.want, tc.decimals)
+		}
+	}
+
+	// 序列化为字符串，结构体字段可以往返
+	type transfer struct {
+		Amount Amount  `json:"amount"`
+		Fee    *Amount `json:"fee"`
+	}
+	in := transfer{Amount: NewAmount(big.NewInt(1500000), 6)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":"1.5","fee":null}` {
+		t.Errorf("序列化结果 %s", data)
+	}
+	var out transfer
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Fee != nil {
+		t.Errorf("null 应该保持为 nil")
+	}
+	// 解析后的精度是小数位数，Rescale 回代币精度后与原值相同
+	if rescaled, err := out.Amount.Rescale(6); err != nil || rescaled.Int().Cmp(in.Amount.Int()) != 0 {
+		t.Errorf("往返后 = %v (%v), 期望 %s", rescaled.Int(), err, in.Amount.Int())
+	}
+}
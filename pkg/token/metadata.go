@@ -0,0 +1,249 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"learn-web3-go/pkg/multicall"
+)
+
+// Multicall3Address Multicall3 在主网和绝大多数测试网上的地址
+var Multicall3Address = multicall.Address
+
+// DefaultDecimals 代币没有实现 decimals() 时使用的精度
+// decimals 在 ERC-20 中是可选方法，钱包和浏览器通常按 18 处理
+const DefaultDecimals = 18
+
+// ErrNotToken 地址上没有合约，或者 name/symbol/decimals 全部调用失败
+var ErrNotToken = errors.New("地址不是 ERC-20 代币")
+
+// Metadata 代币的基本信息
+type Metadata struct {
+	Address  common.Address `json:"address"`
+	Name     string         `json:"name"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+	// DecimalsMissing 为 true 时 Decimals 是 DefaultDecimals，不是合约返回的值
+	DecimalsMissing bool `json:"decimalsMissing,omitempty"`
+}
+
+// Amount 把最小单位的数量包装为代币精度的 Amount
+func (m *Metadata) Amount(value *big.Int) Amount {
+	return NewAmount(value, m.Decimals)
+}
+
+// Format 把最小单位的数量转换为可读的字符串，例如 1500000 -> "1.5"
+func (m *Metadata) Format(value *big.Int) string {
+	return FormatUnits(value, m.Decimals)
+}
+
+// Parse 把可读的数量转换为代币精度的 Amount，小数位超过精度时返回错误
+func (m *Metadata) Parse(s string) (Amount, error) {
+	return ParseAmount(s, m.Decimals)
+}
+
+// Backend Resolver 需要的链上接口，ethclient.Client 和模拟链都满足
+type Backend interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// cacheKey 同一个地址在不同链上可能是不同的代币
+type cacheKey struct {
+	chainID uint64
+	address common.Address
+}
+
+// 所有 Resolver 共享的缓存，name/symbol/decimals 部署后基本不会改变
+var (
+	cacheMu sync.RWMutex
+	cache   = make(map[cacheKey]Metadata)
+)
+
+// Resolver 批量查询代币信息，兼容不标准的代币:
+//   - name/symbol 返回 bytes32 而不是 string (例如 MKR、SAI)
+//   - 没有 decimals() 方法
+//   - 代理合约: 始终查询传入的地址，调用经过代理转发到实现合约并读取代理的存储；
+//     如果传入的是实现合约地址，读到的是实现合约自己的空存储，name 为空、decimals 为 0
+type Resolver struct {
+	backend   Backend
+	multicall common.Address
+
+	mu      sync.Mutex
+	chainID uint64 // 查询成功后缓存，0 表示还没有查询到
+}
+
+// NewResolver 创建 Resolver，使用默认的 Multicall3 地址
+func NewResolver(backend Backend) *Resolver {
+	return &Resolver{backend: backend, multicall: Multicall3Address}
+}
+
+// WithMulticall 使用其他地址的 Multicall3 (例如本地开发链上自己部署的)
+func (r *Resolver) WithMulticall(address common.Address) *Resolver {
+	r.multicall = address
+	return r
+}
+
+// ChainID 返回 backend 所在链的 ID，只缓存成功的结果，查询失败 (例如节点暂时不可用、ctx 超时) 时下次调用会重试
+func (r *Resolver) ChainID(ctx context.Context) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.chainID != 0 {
+		return r.chainID, nil
+	}
+	id, err := r.backend.ChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.chainID = id.Uint64()
+	return r.chainID, nil
+}
+
+// Get 查询单个代币的信息
+func (r *Resolver) Get(ctx context.Context, token common.Address) (*Metadata, error) {
+	result, err := r.Resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return result[token], nil
+}
+
+// Resolve 查询多个代币的信息，没有缓存的代币通过一次 multicall 查询
+// 任意一个地址不是代币时返回 ErrNotToken
+func (r *Resolver) Resolve(ctx context.Context, tokens ...common.Address) (map[common.Address]*Metadata, error) {
+	chainID, err := r.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[common.Address]*Metadata, len(tokens))
+	var missing []common.Address
+	cacheMu.RLock()
+	for _, t := range tokens {
+		if m, ok := cache[cacheKey{chainID, t}]; ok {
+			result[t] = &m
+		} else if _, seen := result[t]; !seen {
+			result[t] = nil
+			missing = append(missing, t)
+		}
+	}
+	cacheMu.RUnlock()
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	// 每个代币 3 个调用: name, symbol, decimals，单个调用失败不影响其他调用
+	// 链上没有 Multicall3 时 (例如本地开发链) Batch 会退回逐个 eth_call
+	batch := multicall.New(r.backend).WithAddress(r.multicall)
+	outputs := make([]*multicall.Future[[]byte], 0, len(missing)*3)
+	for _, t := range missing {
+		for _, data := range [][]byte{nameCall, symbolCall, decimalsCall} {
+			outputs = append(outputs, batch.Raw(t, data))
+		}
+	}
+	if err := batch.Execute(ctx); err != nil {
+		return nil, err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	for i, t := range missing {
+		m, err := decodeMetadata(t, outputs[i*3:i*3+3])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Hex(), err)
+		}
+		cache[cacheKey{chainID, t}] = *m
+		result[t] = m
+	}
+	return result, nil
+}
+
+// 预先编码的调用数据
+var (
+	nameCall     = erc20ABI.Methods["name"].ID
+	symbolCall   = erc20ABI.Methods["symbol"].ID
+	decimalsCall = erc20ABI.Methods["decimals"].ID
+)
+
+// decodeMetadata 解析一个代币的 name, symbol, decimals 返回值
+// 只有调用 revert (multicall.ErrCallFailed) 才说明代币没有这个方法；其它错误直接返回，不能写入缓存，
+// 否则一次网络错误会让 6 位精度的代币永久按 DefaultDecimals 显示
+func decodeMetadata(token common.Address, outputs []*multicall.Future[[]byte]) (*Metadata, error) {
+	for _, out := range outputs {
+		if err := out.Err(); err != nil && !errors.Is(err, multicall.ErrCallFailed) {
+			return nil, err
+		}
+	}
+	name, nameOK := decodeString(outputs[0])
+	symbol, symbolOK := decodeString(outputs[1])
+	decimals, decimalsOK := decodeDecimals(outputs[2])
+	// 对没有代码的地址调用也会成功，只是没有返回值，三个都失败说明不是代币
+	if !nameOK && !symbolOK && !decimalsOK {
+		return nil, ErrNotToken
+	}
+	m := &Metadata{Address: token, Name: name, Symbol: symbol, Decimals: decimals}
+	if !decimalsOK {
+		m.Decimals = DefaultDecimals
+		m.DecimalsMissing = true
+	}
+	return m, nil
+}
+
+var stringArgs = abi.Arguments{{Type: mustType("string")}}
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// decodeString 兼容 string 和 bytes32 两种返回值
+func decodeString(out *multicall.Future[[]byte]) (string, bool) {
+	data, err := out.Get()
+	if err != nil || len(data) < 32 {
+		return "", false
+	}
+	var s string
+	if len(data) == 32 {
+		// bytes32: 右侧补 0
+		s = string(common.TrimRightZeroes(data))
+	} else if values, err := stringArgs.Unpack(data); err == nil {
+		s = values[0].(string)
+	} else {
+		return "", false
+	}
+	if !utf8.ValidString(s) {
+		return "", false
+	}
+	// 去掉控制字符，避免链上数据影响终端或页面显示
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)), true
+}
+
+// decodeDecimals 有的代币把 decimals 声明成 uint256，这里只接受 0-255
+func decodeDecimals(out *multicall.Future[[]byte]) (uint8, bool) {
+	data, err := out.Get()
+	if err != nil || len(data) < 32 {
+		return 0, false
+	}
+	v := new(big.Int).SetBytes(data[:32])
+	if !v.IsUint64() || v.Uint64() > 255 {
+		return 0, false
+	}
+	return uint8(v.Uint64()), true
+}
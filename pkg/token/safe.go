@@ -0,0 +1,183 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"learn-web3-go/contracts/erc20"
+)
+
+// 不标准的 ERC-20:
+//   - USDT 等老代币的 transfer 没有返回值，按 ABI 解码 bool 会失败
+//   - 有的代币失败时返回 false 而不是 revert，交易状态仍然是成功
+//   - 收手续费 (fee-on-transfer) 或通缩/rebase 代币，实际到账少于请求的数量
+//
+// SafeTransfer 发送前先用 eth_call 模拟，兼容没有返回值的情况，此时要求代币地址上有代码 (与 OpenZeppelin SafeERC20 一致)，
+// 上链后再用 VerifyTransfer 对照收据中的 Transfer 日志检查实际转账的数量。
+
+// 转账失败或与预期不一致的原因
+var (
+	ErrTransferReturnedFalse = errors.New("代币的 transfer 返回 false")
+	ErrNoCode                = errors.New("代币地址上没有合约代码")
+	ErrTransferReverted      = errors.New("转账交易执行失败")
+	ErrNoTransferEvent       = errors.New("交易中没有对应的 Transfer 事件")
+	ErrAmountMismatch        = errors.New("实际转账数量与请求的不一致")
+)
+
+// TransferBackend SafeTransfer 和 VerifyTransfer 需要的链上接口，ethclient.Client 满足
+type TransferBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// SafeTransfer 从 auth.From 转 amount 给 to，发送前模拟执行，返回 false 或 revert 时不发送
+func SafeTransfer(auth *bind.TransactOpts, backend TransferBackend, token, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	data, err := erc20ABI.Pack("transfer", to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return safeSend(auth, backend, token, data)
+}
+
+// SafeTransferFrom spender (auth.From) 使用额度把代币从 from 转给 to，检查方式同 SafeTransfer
+func SafeTransferFrom(auth *bind.TransactOpts, backend TransferBackend, token, from, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	data, err := erc20ABI.Pack("transferFrom", from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return safeSend(auth, backend, token, data)
+}
+
+// safeSend 模拟执行后发送交易
+func safeSend(auth *bind.TransactOpts, backend TransferBackend, token common.Address, data []byte) (*types.Transaction, error) {
+	ctx := auth.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	out, err := backend.CallContract(ctx, ethereum.CallMsg{From: auth.From, To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("模拟转账失败: %w", err)
+	}
+	if err := checkReturn(ctx, backend, token, out); err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(token, erc20ABI, backend, backend, backend).RawTransact(auth, data)
+}
+
+// checkReturn 没有返回值时要求代币地址上有代码 (调用没有代码的地址总是成功并且没有返回值)，有返回值时必须是 true
+func checkReturn(ctx context.Context, backend bind.ContractCaller, token common.Address, out []byte) error {
+	if len(out) == 0 {
+		code, err := backend.CodeAt(ctx, token, nil)
+		if err != nil {
+			return err
+		}
+		if len(code) == 0 {
+			return fmt.Errorf("%w: %s", ErrNoCode, token.Hex())
+		}
+		return nil
+	}
+	if len(out) < 32 {
+		return fmt.Errorf("transfer 返回值格式有误: %x", out)
+	}
+	if new(big.Int).SetBytes(out[:32]).Sign() == 0 {
+		return ErrTransferReturnedFalse
+	}
+	return nil
+}
+
+// TransferCheck 上链后的转账核对结果
+type TransferCheck struct {
+	Receipt  *types.Receipt
+	Expected *big.Int // 请求转账的数量
+	Received *big.Int // 日志中 from -> to 的合计
+	// Fee 请求数量与实际到账的差额，大于 0 说明代币收取了手续费
+	Fee *big.Int
+	// Events 交易中该代币的全部 Transfer 事件，包括转给手续费地址或销毁的部分
+	Events []*erc20.ERC20Transfer
+	// RecipientDelta 接收方余额在该区块前后的变化，用于发现 rebase 代币；节点不支持查询历史状态时为 nil
+	// 同一区块中的其它交易也会影响余额，只作为参考，不参与 Err 的判断
+	RecipientDelta *big.Int
+}
+
+// Err 返回核对发现的问题，没有问题时返回 nil
+func (c *TransferCheck) Err() error {
+	switch {
+	case c.Receipt.Status != types.ReceiptStatusSuccessful:
+		return ErrTransferReverted
+	case c.Received.Sign() == 0:
+		return ErrNoTransferEvent
+	case c.Received.Cmp(c.Expected) != 0:
+		return fmt.Errorf("%w: 请求 %s, 日志记录 %s", ErrAmountMismatch, c.Expected, c.Received)
+	}
+	return nil
+}
+
+// VerifyTransfer 等待交易上链，核对收据中 token 的 Transfer 日志是否是 from -> to 转了 amount
+// 返回的 error 与 check.Err() 相同，出现差异时 check 仍然可以查看详细信息
+func VerifyTransfer(ctx context.Context, backend TransferBackend, tx *types.Transaction, token, from, to common.Address, amount *big.Int) (*TransferCheck, error) {
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := erc20.NewERC20Filterer(token, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &TransferCheck{
+		Receipt:  receipt,
+		Expected: new(big.Int).Set(amount),
+		Received: new(big.Int),
+	}
+	transferTopic := erc20ABI.Events["Transfer"].ID
+	for _, l := range receipt.Logs {
+		if l.Address != token || len(l.Topics) != 3 || l.Topics[0] != transferTopic {
+			continue
+		}
+		event, err := filterer.ParseTransfer(*l)
+		if err != nil {
+			continue
+		}
+		check.Events = append(check.Events, event)
+		if event.From == from && event.To == to {
+			check.Received.Add(check.Received, event.Value)
+		}
+	}
+	check.Fee = new(big.Int).Sub(check.Expected, check.Received)
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		check.RecipientDelta = balanceDelta(ctx, backend, token, to, receipt.BlockNumber)
+	}
+	return check, check.Err()
+}
+
+// balanceDelta 查询 account 在区块 number 前后的余额差，失败时返回 nil
+func balanceDelta(ctx context.Context, backend TransferBackend, token, account common.Address, number *big.Int) *big.Int {
+	caller, err := erc20.NewERC20Caller(token, backend)
+	if err != nil || number.Sign() == 0 {
+		return nil
+	}
+	after, err := caller.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: number}, account)
+	if err != nil {
+		return nil
+	}
+	before, err := caller.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).Sub(number, big.NewInt(1))}, account)
+	if err != nil {
+		return nil
+	}
+	return new(big.Int).Sub(after, before)
+}
+
+var erc20ABI = func() abi.ABI {
+	parsed, err := erc20.ERC20MetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	return *parsed
+}()
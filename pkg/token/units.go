@@ -0,0 +1,68 @@
+package token
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits 把最小单位的数量按精度转换为十进制字符串，去掉小数末尾的 0
+// 例如 FormatUnits(1500000, 6) = "1.5"，不经过浮点数，不会丢失精度
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	neg := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	d := int(decimals)
+	if len(digits) <= d {
+		digits = strings.Repeat("0", d-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-d], strings.TrimRight(digits[len(digits)-d:], "0")
+	s := intPart
+	if fracPart != "" {
+		s += "." + fracPart
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// ParseUnits 把十进制字符串按精度转换为最小单位
+// 小数位超过精度时返回错误而不是截断，例如 6 位精度的代币不能转 0.0000001
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	raw := s
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("无效的数量: %q", raw)
+	}
+	if trimmed := strings.TrimRight(fracPart, "0"); len(trimmed) > int(decimals) {
+		return nil, fmt.Errorf("数量 %q 的小数位超过代币精度 %d", raw, decimals)
+	}
+	if len(fracPart) > int(decimals) {
+		fracPart = fracPart[:decimals]
+	}
+	fracPart += strings.Repeat("0", int(decimals)-len(fracPart))
+
+	v, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return nil, fmt.Errorf("无效的数量: %q", raw)
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// DefaultDerivationPath 以太坊第一个账户的 BIP-44 路径
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// MnemonicToSeed 助记词 (Mnemonic) -> 种子 (Seed)，与 bip39.NewSeed 结果一致
+// 这里直接使用 []byte，方便调用方用完之后清零
+func MnemonicToSeed(mnemonic, passphrase []byte) []byte {
+	salt := append([]byte("mnemonic"), passphrase...)
+	defer ZeroBytes(salt)
+	return pbkdf2.Key(mnemonic, salt, 2048, 64, sha512.New)
+}
+
+// DeriveKey 按 BIP-32 路径从种子推导私钥
+func DeriveKey(seed []byte, path string) (*ecdsa.PrivateKey, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 主私钥 = HMAC-SHA512("Bitcoin seed", seed)
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	defer ZeroBytes(sum)
+
+	key := append([]byte{}, sum[:32]...)
+	chainCode := append([]byte{}, sum[32:]...)
+	defer ZeroBytes(chainCode)
+
+	for _, index := range derivationPath {
+		next, nextChainCode, err := deriveChild(key, chainCode, index)
+		ZeroBytes(key)
+		if err != nil {
+			return nil, err
+		}
+		key = next
+		copy(chainCode, nextChainCode)
+		ZeroBytes(nextChainCode)
+	}
+	defer ZeroBytes(key)
+
+	return crypto.ToECDSA(key)
+}
+
+// DeriveAddress 按 BIP-32 路径从种子推导私钥和地址
+func DeriveAddress(seed []byte, path string) (*ecdsa.PrivateKey, common.Address, error) {
+	key, err := DeriveKey(seed, path)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+// deriveChild 推导一个子私钥
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= 0x80000000 {
+		// 硬化推导: 0x00 || 私钥 || index
+		data = append(data, 0x00)
+		data = append(data, key...)
+	} else {
+		// 普通推导: 压缩公钥 || index
+		priv, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&priv.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	defer ZeroBytes(data)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	defer ZeroBytes(sum)
+
+	// 子私钥 = (IL + 父私钥) mod N
+	il := new(big.Int).SetBytes(sum[:32])
+	n := crypto.S256().Params().N
+	if il.Cmp(n) >= 0 {
+		return nil, nil, errors.New("无效的子私钥，请换一个索引")
+	}
+	child := il.Add(il, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errors.New("无效的子私钥，请换一个索引")
+	}
+	return common.LeftPadBytes(child.Bytes(), 32), append([]byte{}, sum[32:]...), nil
+}
@@ -0,0 +1,41 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// SaveToKeystore 将私钥加密写入 keystore 目录，私钥本身不会输出到任何地方
+func SaveToKeystore(ksDir, password string, key *ecdsa.PrivateKey) (accounts.Account, error) {
+	if password == "" {
+		return accounts.Account{}, errors.New("keystore 密码不能为空")
+	}
+	// 确保存在这个目录
+	if err := os.MkdirAll(ksDir, 0700); err != nil {
+		return accounts.Account{}, err
+	}
+	ks := keystore.NewKeyStore(ksDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	return ks.ImportECDSA(key, password)
+}
+
+// ZeroKey 清空私钥在内存中的数据
+func ZeroKey(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	b := key.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ZeroBytes 清空字节切片中的敏感数据
+func ZeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,289 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Shamir 秘密分享: 在 GF(256) 上把秘密拆成 N 份，任意 M 份可以还原
+// 注意: 分片使用本项目自己的文本格式，不兼容 SLIP-39 助记词分片
+// (SLIP-39 需要专用的 1024 词表、RS1024 校验和 Feistel 加密，这里没有实现)
+
+const sharePrefix = "shamir1"
+
+// 分片中保存的秘密类型
+const (
+	ShareKindKey     = "key"     // 32 字节私钥
+	ShareKindEntropy = "entropy" // BIP-39 助记词的熵
+)
+
+// Share 一个秘密分片
+type Share struct {
+	Kind      string // 秘密类型
+	ID        uint16 // 同一组分片的随机编号，防止混用不同批次的分片
+	Threshold int    // 还原需要的分片数量
+	Total     int    // 分片总数
+	Index     byte   // 分片的 x 坐标，从 1 开始
+	Value     []byte // 分片的 y 值
+}
+
+// SplitSecret 把秘密拆成 total 份，threshold 份即可还原
+func SplitSecret(kind string, secret []byte, threshold, total int) ([]Share, error) {
+	if kind != ShareKindKey && kind != ShareKindEntropy {
+		return nil, fmt.Errorf("不支持的秘密类型: %s", kind)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("秘密不能为空")
+	}
+	// 门限为 1 时多项式只有常数项，每个分片都是明文的秘密
+	if threshold < 2 || threshold > total || total > 255 {
+		return nil, errors.New("需要满足 2 <= 门限 <= 分片数 <= 255")
+	}
+
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	id := uint16(idBytes[0])<<8 | uint16(idBytes[1])
+
+	shares := make([]Share, total)
+	for i := range shares {
+		shares[i] = Share{
+			Kind:      kind,
+			ID:        id,
+			Threshold: threshold,
+			Total:     total,
+			Index:     byte(i + 1),
+			Value:     make([]byte, len(secret)),
+		}
+	}
+
+	// 每个字节单独构造一个 threshold-1 次多项式，常数项就是秘密
+	coeffs := make([]byte, threshold)
+	defer ZeroBytes(coeffs)
+	for pos, b := range secret {
+		coeffs[0] = b
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			shares[i].Value[pos] = evalPolynomial(coeffs, shares[i].Index)
+		}
+	}
+	return shares, nil
+}
+
+// CombineShares 用拉格朗日插值还原秘密
+func CombineShares(shares []Share) (string, []byte, error) {
+	if len(shares) == 0 {
+		return "", nil, errors.New("没有提供分片")
+	}
+	first := shares[0]
+	if len(shares) < first.Threshold {
+		return "", nil, fmt.Errorf("分片数量不足，需要 %d 份，只提供了 %d 份", first.Threshold, len(shares))
+	}
+
+	seen := make(map[byte]bool)
+	for _, s := range shares {
+		if err := s.validate(); err != nil {
+			return "", nil, err
+		}
+		if s.Kind != first.Kind || s.ID != first.ID || s.Threshold != first.Threshold || s.Total != first.Total || len(s.Value) != len(first.Value) {
+			return "", nil, errors.New("分片不属于同一组")
+		}
+		if seen[s.Index] {
+			return "", nil, fmt.Errorf("分片编号重复: %d", s.Index)
+		}
+		seen[s.Index] = true
+	}
+
+	// 只需要门限数量的分片
+	shares = shares[:first.Threshold]
+	secret := make([]byte, len(first.Value))
+	for pos := range secret {
+		var value byte
+		for i, si := range shares {
+			// 基函数 l_i(0) = Π x_j / (x_j - x_i)，GF(256) 上减法就是异或
+			basis := byte(1)
+			for j, sj := range shares {
+				if i == j {
+					continue
+				}
+				basis = gfMul(basis, gfDiv(sj.Index, sj.Index^si.Index))
+			}
+			value ^= gfMul(si.Value[pos], basis)
+		}
+		secret[pos] = value
+	}
+	return first.Kind, secret, nil
+}
+
+// RecoverKey 还原私钥，并与期望的地址比对
+func RecoverKey(shares []Share, expected common.Address) (*ecdsa.PrivateKey, error) {
+	kind, secret, err := CombineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(secret)
+	if kind != ShareKindKey {
+		return nil, fmt.Errorf("分片类型是 %s，不是私钥", kind)
+	}
+	key, err := crypto.ToECDSA(secret)
+	if err != nil {
+		return nil, err
+	}
+	if address := crypto.PubkeyToAddress(key.PublicKey); address != expected {
+		ZeroKey(key)
+		return nil, fmt.Errorf("还原出的地址 %s 与期望的地址不一致", address.Hex())
+	}
+	return key, nil
+}
+
+// RecoverMnemonic 还原 BIP-39 助记词，并与默认路径下的期望地址比对
+func RecoverMnemonic(shares []Share, expected common.Address) ([]byte, error) {
+	kind, entropy, err := CombineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(entropy)
+	if kind != ShareKindEntropy {
+		return nil, fmt.Errorf("分片类型是 %s，不是助记词", kind)
+	}
+	words, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic := []byte(words)
+
+	seed := MnemonicToSeed(mnemonic, nil)
+	defer ZeroBytes(seed)
+	key, address, err := DeriveAddress(seed, DefaultDerivationPath)
+	if err != nil {
+		ZeroBytes(mnemonic)
+		return nil, err
+	}
+	ZeroKey(key)
+	if address != expected {
+		ZeroBytes(mnemonic)
+		return nil, fmt.Errorf("还原出的地址 %s 与期望的地址不一致", address.Hex())
+	}
+	return mnemonic, nil
+}
+
+// String 编码成带校验和的文本，例如 shamir1-key-1a2b-2of3-1-<hex>-<checksum>
+func (s Share) String() string {
+	body := fmt.Sprintf("%s-%s-%04x-%dof%d-%d-%s",
+		sharePrefix, s.Kind, s.ID, s.Threshold, s.Total, s.Index, hex.EncodeToString(s.Value))
+	return body + "-" + shareChecksum(body)
+}
+
+// ParseShare 解析文本格式的分片，并检查校验和
+func ParseShare(text string) (Share, error) {
+	text = strings.TrimSpace(text)
+	idx := strings.LastIndex(text, "-")
+	if idx < 0 {
+		return Share{}, errors.New("分片格式有误")
+	}
+	body, checksum := text[:idx], text[idx+1:]
+	if shareChecksum(body) != strings.ToLower(checksum) {
+		return Share{}, errors.New("分片校验和不匹配，可能抄写错误")
+	}
+
+	parts := strings.Split(body, "-")
+	if len(parts) != 6 || parts[0] != sharePrefix {
+		return Share{}, errors.New("分片格式有误")
+	}
+	s := Share{Kind: parts[1]}
+
+	id, err := strconv.ParseUint(parts[2], 16, 16)
+	if err != nil {
+		return Share{}, errors.New("分片编号有误")
+	}
+	s.ID = uint16(id)
+
+	if _, err := fmt.Sscanf(parts[3], "%dof%d", &s.Threshold, &s.Total); err != nil {
+		return Share{}, errors.New("分片门限格式有误")
+	}
+	index, err := strconv.ParseUint(parts[4], 10, 8)
+	if err != nil {
+		return Share{}, errors.New("分片序号有误")
+	}
+	s.Index = byte(index)
+
+	if s.Value, err = hex.DecodeString(parts[5]); err != nil {
+		return Share{}, errors.New("分片数据有误")
+	}
+	if err := s.validate(); err != nil {
+		return Share{}, err
+	}
+	return s, nil
+}
+
+// validate 检查门限、分片数和序号，与 SplitSecret 的限制一致
+// 校验和只能发现抄写错误，手工构造的 "1of1" 分片同样能通过校验和
+func (s Share) validate() error {
+	if s.Threshold < 2 || s.Threshold > s.Total || s.Total > 255 {
+		return fmt.Errorf("分片门限有误: %dof%d, 需要满足 2 <= 门限 <= 分片数 <= 255", s.Threshold, s.Total)
+	}
+	if s.Index == 0 || int(s.Index) > s.Total {
+		return fmt.Errorf("分片序号有误: %d, 需要在 1 到 %d 之间", s.Index, s.Total)
+	}
+	if len(s.Value) == 0 {
+		return errors.New("分片数据为空")
+	}
+	return nil
+}
+
+// shareChecksum keccak256 的前 4 个字节
+func shareChecksum(body string) string {
+	return hex.EncodeToString(crypto.Keccak256([]byte(body))[:4])
+}
+
+// evalPolynomial 霍纳法则计算多项式在 x 处的值
+func evalPolynomial(coeffs []byte, x byte) byte {
+	var result byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coeffs[i]
+	}
+	return result
+}
+
+// gfMul GF(256) 乘法，不可约多项式 x^8 + x^4 + x^3 + x + 1 (与 AES、SLIP-39 相同)
+func gfMul(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv GF(256) 求逆，a^254 = a^-1
+func gfInv(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = gfMul(result, a)
+	}
+	return result
+}
+
+// gfDiv GF(256) 除法
+func gfDiv(a, b byte) byte {
+	return gfMul(a, gfInv(b))
+}
@@ -0,0 +1,193 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var hexCharsRe = regexp.MustCompile("^[0-9a-fA-F]*$")
+
+// VanityOptions 靓号地址的匹配条件
+type VanityOptions struct {
+	Prefix        string         // 地址前缀（不含 0x）
+	Suffix        string         // 地址后缀
+	Regex         *regexp.Regexp // 自定义正则，匹配去掉 0x 的 40 位地址
+	CaseSensitive bool           // 是否按 EIP-55 校验和大小写匹配
+	Workers       int            // 并发协程数，<= 0 时使用全部 CPU 核心
+}
+
+// VanityProgress 搜索进度
+type VanityProgress struct {
+	Attempts    uint64        // 已尝试的私钥数量
+	Elapsed     time.Duration // 已耗时
+	Rate        float64       // 每秒尝试次数
+	Probability float64       // 到目前为止应该已经找到的概率 (0~1)，正则模式下为 0
+	ETA         time.Duration // 预计剩余时间，正则模式下为 0
+}
+
+// VanityResult 搜索结果
+type VanityResult struct {
+	PrivateKey *ecdsa.PrivateKey
+	Address    common.Address
+	Attempts   uint64
+	Elapsed    time.Duration
+}
+
+// Validate 检查匹配条件是否合法
+func (o *VanityOptions) Validate() error {
+	o.Prefix = strings.TrimPrefix(o.Prefix, "0x")
+	if o.Prefix == "" && o.Suffix == "" && o.Regex == nil {
+		return errors.New("前缀、后缀、正则至少需要指定一个")
+	}
+	if !hexCharsRe.MatchString(o.Prefix) || !hexCharsRe.MatchString(o.Suffix) {
+		return errors.New("前缀和后缀只能包含十六进制字符")
+	}
+	if len(o.Prefix)+len(o.Suffix) > common.AddressLength*2 {
+		return errors.New("前缀和后缀的总长度超过了地址长度")
+	}
+	return nil
+}
+
+// Difficulty 估算平均需要尝试的次数，正则模式下无法估算，返回 0
+func (o *VanityOptions) Difficulty() float64 {
+	if o.Regex != nil {
+		return 0
+	}
+	difficulty := 1.0
+	for _, ch := range o.Prefix + o.Suffix {
+		difficulty *= 16
+		// 大小写敏感时，字母的大小写由校验和决定，各占一半的概率
+		if o.CaseSensitive && (ch < '0' || ch > '9') {
+			difficulty *= 2
+		}
+	}
+	return difficulty
+}
+
+// match 判断地址是否满足条件
+func (o *VanityOptions) match(addr common.Address) bool {
+	var hexAddr string
+	if o.CaseSensitive {
+		hexAddr = addr.Hex()[2:] // EIP-55 校验和格式
+	} else {
+		hexAddr = strings.ToLower(addr.Hex()[2:])
+	}
+
+	prefix, suffix := o.Prefix, o.Suffix
+	if !o.CaseSensitive {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+	if !strings.HasPrefix(hexAddr, prefix) || !strings.HasSuffix(hexAddr, suffix) {
+		return false
+	}
+	if o.Regex != nil && !o.Regex.MatchString(hexAddr) {
+		return false
+	}
+	return true
+}
+
+// GenerateVanity 使用多个 CPU 核心搜索满足条件的地址
+// onProgress 每隔 interval 回调一次，可以为 nil
+func GenerateVanity(ctx context.Context, opts VanityOptions, interval time.Duration, onProgress func(VanityProgress)) (*VanityResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts atomic.Uint64
+		once     sync.Once
+		result   *VanityResult
+		wg       sync.WaitGroup
+		start    = time.Now()
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				key, err := crypto.GenerateKey()
+				if err != nil {
+					continue
+				}
+				n := attempts.Add(1)
+				addr := crypto.PubkeyToAddress(key.PublicKey)
+				if !opts.match(addr) {
+					continue
+				}
+				once.Do(func() {
+					result = &VanityResult{
+						PrivateKey: key,
+						Address:    addr,
+						Attempts:   n,
+						Elapsed:    time.Since(start),
+					}
+					cancel()
+				})
+				return
+			}
+		}()
+	}
+
+	// 定时汇报进度
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	if onProgress != nil && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-done:
+				break loop
+			case <-ticker.C:
+				onProgress(progress(attempts.Load(), time.Since(start), opts.Difficulty()))
+			}
+		}
+	}
+	<-done
+
+	if result == nil {
+		return nil, ctx.Err()
+	}
+	return result, nil
+}
+
+// progress 根据尝试次数和难度计算进度
+// 每次尝试相互独立（几何分布），所以剩余时间始终按平均难度估算
+func progress(attempts uint64, elapsed time.Duration, difficulty float64) VanityProgress {
+	p := VanityProgress{Attempts: attempts, Elapsed: elapsed}
+	if elapsed > 0 {
+		p.Rate = float64(attempts) / elapsed.Seconds()
+	}
+	if difficulty > 0 && p.Rate > 0 {
+		p.Probability = 1 - math.Pow(1-1/difficulty, float64(attempts))
+		eta := difficulty / p.Rate * float64(time.Second)
+		if eta > math.MaxInt64 {
+			eta = math.MaxInt64
+		}
+		p.ETA = time.Duration(eta)
+	}
+	return p
+}
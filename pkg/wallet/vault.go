@@ -0,0 +1,342 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// VaultVersion 当前助记词保险箱的文件格式版本
+	VaultVersion = 1
+
+	vaultKDF    = "scrypt"
+	vaultCipher = "aes-256-gcm"
+)
+
+// 默认的 scrypt 参数，与 keystore.StandardScryptN/StandardScryptP 保持一致
+const (
+	VaultScryptN = 1 << 18
+	VaultScryptR = 8
+	VaultScryptP = 1
+)
+
+// 读取保险箱时允许的 scrypt 参数范围，防止篡改的文件要求过大的内存或计算量
+const (
+	vaultMinScryptN   = 1 << 10
+	vaultMaxScryptN   = 1 << 20
+	vaultMaxScryptR   = 32
+	vaultMaxScryptP   = 16
+	vaultMaxScryptMem = 1 << 30 // scrypt 需要 128*N*r 字节内存，最多 1 GiB
+)
+
+var ErrVaultPassword = errors.New("密码错误或者保险箱文件已经损坏了")
+
+// VaultHeader 保险箱的文件头，会作为 GCM 的附加数据参与认证，篡改后无法解密
+type VaultHeader struct {
+	Version int          `json:"version"`
+	KDF     string       `json:"kdf"`
+	Params  ScryptParams `json:"kdfparams"`
+	Cipher  string       `json:"cipher"`
+	Address string       `json:"address"` // 默认路径下的第一个地址，方便识别，不含敏感信息
+}
+
+// ScryptParams scrypt 参数
+type ScryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+// validate 检查 scrypt 参数是否在合理范围内，N 必须是 2 的幂
+func (p ScryptParams) validate() error {
+	if p.N < vaultMinScryptN || p.N > vaultMaxScryptN || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("scrypt 参数 N=%d 无效, 必须是 %d 到 %d 之间 2 的幂", p.N, vaultMinScryptN, vaultMaxScryptN)
+	}
+	if p.R < 1 || p.R > vaultMaxScryptR {
+		return fmt.Errorf("scrypt 参数 r=%d 无效, 必须在 1 到 %d 之间", p.R, vaultMaxScryptR)
+	}
+	if p.P < 1 || p.P > vaultMaxScryptP {
+		return fmt.Errorf("scrypt 参数 p=%d 无效, 必须在 1 到 %d 之间", p.P, vaultMaxScryptP)
+	}
+	if 128*p.N*p.R > vaultMaxScryptMem {
+		return fmt.Errorf("scrypt 参数 N=%d r=%d 需要的内存超过 %d MiB", p.N, p.R, vaultMaxScryptMem>>20)
+	}
+	return nil
+}
+
+// Vault 加密存储的助记词文件
+type Vault struct {
+	Header     VaultHeader `json:"header"`
+	Nonce      string      `json:"nonce"`
+	Ciphertext string      `json:"ciphertext"`
+}
+
+// UnlockedVault 解锁后的保险箱，使用完必须调用 Close 清空内存
+type UnlockedVault struct {
+	mnemonic []byte
+}
+
+// NewVault 用密码加密助记词，生成一个新的保险箱
+func NewVault(mnemonic, password []byte) (*Vault, error) {
+	// bip39 只接受 string，这里的校验会产生一份无法清零的副本
+	if !bip39.IsMnemonicValid(string(mnemonic)) {
+		return nil, errors.New("助记词无效")
+	}
+
+	// 推导默认地址写入文件头
+	seed := MnemonicToSeed(mnemonic, nil)
+	defer ZeroBytes(seed)
+	key, address, err := DeriveAddress(seed, DefaultDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	ZeroKey(key)
+
+	v := &Vault{
+		Header: VaultHeader{
+			Version: VaultVersion,
+			KDF:     vaultKDF,
+			Cipher:  vaultCipher,
+			Address: address.Hex(),
+		},
+	}
+	if err := v.seal(mnemonic, password); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewMnemonic 生成新的 BIP-39 助记词，bits 为熵长度: 128 (12 个单词) 或 256 (24 个单词)
+// 返回 []byte 方便使用后清零，bip39 内部的 string 副本无法清零
+func NewMnemonic(bits int) ([]byte, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(entropy)
+	words, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(words), nil
+}
+
+// CreateVaultFile 创建保险箱文件: 从环境变量 passwordEnv 读取密码，加密 mnemonic 后写入 path
+// mnemonic 为空时生成 bits 位熵的新助记词；path 已经存在时返回错误，不会覆盖已有的保险箱
+// 返回的助记词是副本，调用方用完需要自行 ZeroBytes
+func CreateVaultFile(path string, mnemonic []byte, bits int, passwordEnv string) (*Vault, []byte, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, nil, fmt.Errorf("保险箱文件已经存在: %s", path)
+	}
+	password, err := ReadPassword(passwordEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ZeroBytes(password)
+
+	if len(mnemonic) == 0 {
+		if mnemonic, err = NewMnemonic(bits); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		mnemonic = append([]byte{}, mnemonic...)
+	}
+
+	v, err := NewVault(mnemonic, password)
+	if err != nil {
+		ZeroBytes(mnemonic)
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		ZeroBytes(mnemonic)
+		return nil, nil, err
+	}
+	if err := v.Save(path); err != nil {
+		ZeroBytes(mnemonic)
+		return nil, nil, err
+	}
+	return v, mnemonic, nil
+}
+
+// LoadVault 从文件读取保险箱
+func LoadVault(path string) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	v := new(Vault)
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("保险箱文件格式有误: %w", err)
+	}
+	if v.Header.Version != VaultVersion {
+		return nil, fmt.Errorf("不支持的保险箱版本: %d", v.Header.Version)
+	}
+	if v.Header.KDF != vaultKDF || v.Header.Cipher != vaultCipher {
+		return nil, fmt.Errorf("不支持的加密算法: %s/%s", v.Header.KDF, v.Header.Cipher)
+	}
+	if err := v.Header.Params.validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Save 将保险箱写入文件，只有当前用户可读写
+func (v *Vault) Save(path string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免写到一半损坏原文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Address 返回文件头记录的默认地址
+func (v *Vault) Address() common.Address {
+	return common.HexToAddress(v.Header.Address)
+}
+
+// Unlock 用密码解锁保险箱
+func (v *Vault) Unlock(password []byte) (*UnlockedVault, error) {
+	aead, err := newVaultAEAD(v.Header.Params, password)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(v.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("保险箱 nonce 格式有误")
+	}
+	ciphertext, err := hex.DecodeString(v.Ciphertext)
+	if err != nil {
+		return nil, errors.New("保险箱密文格式有误")
+	}
+	// 文件头的 JSON 作为附加数据
+	ad, err := json.Marshal(v.Header)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, ErrVaultPassword
+	}
+	return &UnlockedVault{mnemonic: mnemonic}, nil
+}
+
+// Rekey 更换保险箱的密码，同时更换 salt 和 nonce
+func (v *Vault) Rekey(oldPassword, newPassword []byte) error {
+	unlocked, err := v.Unlock(oldPassword)
+	if err != nil {
+		return err
+	}
+	defer unlocked.Close()
+	return v.seal(unlocked.mnemonic, newPassword)
+}
+
+// seal 生成新的 salt 和 nonce，并加密助记词
+func (v *Vault) seal(mnemonic, password []byte) error {
+	if len(password) == 0 {
+		return errors.New("保险箱密码不能为空")
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	// 先在副本上加密，成功之后再替换，失败时原保险箱保持不变
+	header := v.Header
+	header.Params = ScryptParams{
+		N:    VaultScryptN,
+		R:    VaultScryptR,
+		P:    VaultScryptP,
+		Salt: hex.EncodeToString(salt),
+	}
+
+	aead, err := newVaultAEAD(header.Params, password)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	ad, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	v.Header = header
+	v.Nonce = hex.EncodeToString(nonce)
+	v.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, mnemonic, ad))
+	return nil
+}
+
+// newVaultAEAD 通过 scrypt 从密码推导 AES-256 密钥
+func newVaultAEAD(p ScryptParams, password []byte) (cipher.AEAD, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(p.Salt)
+	if err != nil {
+		return nil, errors.New("保险箱 salt 格式有误")
+	}
+	key, err := scrypt.Key(password, salt, p.N, p.R, p.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Mnemonic 返回助记词的副本，调用方用完需要自行 ZeroBytes
+func (u *UnlockedVault) Mnemonic() []byte {
+	return append([]byte{}, u.mnemonic...)
+}
+
+// Derive 按 BIP-32 路径推导账户，passphrase 为 BIP-39 的额外密码，可以为空
+func (u *UnlockedVault) Derive(path string, passphrase []byte) (*ecdsa.PrivateKey, common.Address, error) {
+	if u.mnemonic == nil {
+		return nil, common.Address{}, errors.New("保险箱已经关闭")
+	}
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	seed := MnemonicToSeed(u.mnemonic, passphrase)
+	defer ZeroBytes(seed)
+	return DeriveAddress(seed, path)
+}
+
+// Close 清空内存中的助记词
+func (u *UnlockedVault) Close() {
+	ZeroBytes(u.mnemonic)
+	u.mnemonic = nil
+}
+
+// ReadPassword 从环境变量读取密码并转成 []byte，方便使用后清零
+// 只去掉末尾的换行，密码首尾的空格是密码的一部分
+func ReadPassword(env string) ([]byte, error) {
+	pw := strings.TrimRight(os.Getenv(env), "\r\n")
+	if pw == "" {
+		return nil, fmt.Errorf("缺少 %s", env)
+	}
+	return []byte(pw), nil
+}